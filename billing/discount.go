@@ -0,0 +1,145 @@
+package billing
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// ValidateCoupon checks that the coupon has exactly one kind of discount and a consistent duration
+func ValidateCoupon(coupon types.Coupon) error {
+	hasPercent := coupon.PercentOff != 0
+	hasAmount := coupon.AmountOffCents != 0
+	switch {
+	case hasPercent == hasAmount:
+		return fmt.Errorf("billing: coupon %s needs exactly one of percent_off and amount_off", coupon.ID)
+	case hasPercent && (coupon.PercentOff < 0 || coupon.PercentOff > 100):
+		return fmt.Errorf("billing: coupon %s percent_off %v is outside 0-100", coupon.ID, coupon.PercentOff)
+	case hasAmount && coupon.AmountOffCents < 0:
+		return fmt.Errorf("billing: coupon %s amount_off must not be negative", coupon.ID)
+	case hasAmount && coupon.Currency == "":
+		return fmt.Errorf("billing: coupon %s amount_off needs a currency", coupon.ID)
+	}
+
+	switch coupon.Duration {
+	case types.CouponDurationOnce, types.CouponDurationForever:
+		if coupon.DurationInMonths != 0 {
+			return fmt.Errorf("billing: coupon %s duration %s takes no duration_in_months", coupon.ID, coupon.Duration)
+		}
+	case types.CouponDurationRepeating:
+		if coupon.DurationInMonths <= 0 {
+			return fmt.Errorf("billing: coupon %s repeating duration needs duration_in_months", coupon.ID)
+		}
+	default:
+		return fmt.Errorf("billing: coupon %s has unknown duration %q", coupon.ID, coupon.Duration)
+	}
+	return nil
+}
+
+// NewDiscount applies the coupon from start. Repeating coupons end
+// DurationInMonths calendar months later, on the last day of shorter months;
+// once and forever coupons have no end.
+func NewDiscount(coupon types.Coupon, promotionCode string, start time.Time) (*types.Discount, error) {
+	if err := ValidateCoupon(coupon); err != nil {
+		return nil, err
+	}
+	discount := &types.Discount{
+		Coupon:        coupon,
+		PromotionCode: promotionCode,
+		StartedAt:     start,
+	}
+	if coupon.Duration == types.CouponDurationRepeating {
+		endsAt := addMonths(start, coupon.DurationInMonths, start.Day())
+		discount.EndsAt = &endsAt
+	}
+	return discount, nil
+}
+
+// DiscountAmount returns what the coupon takes off one subtotal, ignoring its duration.
+// Percentages round half up to the cent and fixed amounts never exceed the subtotal.
+func DiscountAmount(coupon types.Coupon, subtotalCents int64, currency string) (int64, error) {
+	if subtotalCents <= 0 {
+		return 0, nil
+	}
+	if coupon.PercentOff != 0 {
+		return int64(math.Floor(float64(subtotalCents)*coupon.PercentOff/100 + 0.5)), nil
+	}
+	if !strings.EqualFold(coupon.Currency, currency) {
+		return 0, fmt.Errorf("billing: coupon %s is in %s, not %s", coupon.ID, coupon.Currency, currency)
+	}
+	return min(coupon.AmountOffCents, subtotalCents), nil
+}
+
+// InvoiceDiscount returns what the discount takes off an invoice for the period starting at periodStart.
+// Periods starting before the discount are not discounted. Once coupons only apply to the
+// first invoice after the discount started, repeating coupons to periods starting before
+// EndsAt, and forever coupons to every period. A nil discount takes nothing off.
+func InvoiceDiscount(discount *types.Discount, subtotalCents int64, currency string, periodStart time.Time, firstInvoice bool) (int64, error) {
+	if discount == nil || periodStart.Before(discount.StartedAt) {
+		return 0, nil
+	}
+	switch discount.Coupon.Duration {
+	case types.CouponDurationOnce:
+		if !firstInvoice {
+			return 0, nil
+		}
+	case types.CouponDurationRepeating:
+		if discount.EndsAt == nil || !periodStart.Before(*discount.EndsAt) {
+			return 0, nil
+		}
+	}
+	return DiscountAmount(discount.Coupon, subtotalCents, currency)
+}
+
+// CheckPromotionCode returns the reason the code cannot be redeemed for the tier at the given time,
+// as one of the types.PromotionCode* constants, or "" when it can.
+func CheckPromotionCode(code *types.PromotionCode, tier types.SubscriptionTier, at time.Time) string {
+	switch {
+	case code == nil:
+		return types.PromotionCodeNotFound
+	case !code.Active:
+		return types.PromotionCodeInactive
+	case code.ExpiresAt != nil && !at.Before(*code.ExpiresAt):
+		return types.PromotionCodeExpired
+	case code.MaxRedemptions > 0 && code.TimesRedeemed >= code.MaxRedemptions:
+		return types.PromotionCodeFullyRedeemed
+	case len(code.Tiers) > 0 && !containsTier(code.Tiers, tier):
+		return types.PromotionCodeTierNotEligible
+	}
+	return ""
+}
+
+// ValidatePromotionCode builds the ValidatePromotionCodeResponse for a looked-up code, which may be nil
+func ValidatePromotionCode(code *types.PromotionCode, tier types.SubscriptionTier, at time.Time) *types.ValidatePromotionCodeResponse {
+	if reason := CheckPromotionCode(code, tier, at); reason != "" {
+		return &types.ValidatePromotionCodeResponse{Valid: false, Reason: reason}
+	}
+	return &types.ValidatePromotionCodeResponse{Valid: true, PromotionCode: code}
+}
+
+// CheckTrialExtension validates an admin trial extension at the given time.
+// Only trialing subscriptions can be extended, and the new end must be later than both now and the current end.
+func CheckTrialExtension(sub *types.SubscriptionResponse, req types.ExtendTrialRequest, at time.Time) error {
+	if sub.Status != types.SubscriptionStatusTrialing {
+		return types.ErrBadRequest.WithDetails(fmt.Sprintf("subscription is %s, not trialing", sub.Status))
+	}
+	if !req.TrialEndsAt.After(at) {
+		return types.ErrBadRequest.WithDetails("trial end must be in the future")
+	}
+	if sub.TrialEndsAt != nil && !req.TrialEndsAt.After(*sub.TrialEndsAt) {
+		return types.ErrBadRequest.WithDetails("trial end must be later than the current one")
+	}
+	return nil
+}
+
+func containsTier(tiers []types.SubscriptionTier, tier types.SubscriptionTier) bool {
+	for _, t := range tiers {
+		if t == tier {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,202 @@
+package billing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+func TestDiscountAmount(t *testing.T) {
+	percent := func(p float64) types.Coupon {
+		return types.Coupon{ID: "pct", PercentOff: p, Duration: types.CouponDurationForever}
+	}
+	amount := func(cents int64, currency string) types.Coupon {
+		return types.Coupon{ID: "amt", AmountOffCents: cents, Currency: currency, Duration: types.CouponDurationForever}
+	}
+
+	tests := []struct {
+		name     string
+		coupon   types.Coupon
+		subtotal int64
+		currency string
+		want     int64
+		wantErr  bool
+	}{
+		{"percent exact", percent(20), 1000, "usd", 200, false},
+		{"percent rounds half up", percent(50), 3, "usd", 2, false},
+		{"percent rounds down below half", percent(15), 1003, "usd", 150, false},
+		{"percent rounds up above half", percent(15), 1007, "usd", 151, false},
+		{"hundred percent", percent(100), 999, "usd", 999, false},
+		{"fixed amount", amount(500, "usd"), 2000, "usd", 500, false},
+		{"fixed amount capped at subtotal", amount(500, "usd"), 300, "usd", 300, false},
+		{"currency compared case-insensitively", amount(500, "USD"), 2000, "usd", 500, false},
+		{"currency mismatch", amount(500, "eur"), 2000, "usd", 0, true},
+		{"zero subtotal", amount(500, "eur"), 0, "usd", 0, false},
+		{"negative subtotal", percent(50), -100, "usd", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DiscountAmount(tt.coupon, tt.subtotal, tt.currency)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DiscountAmount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("DiscountAmount() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDiscountEndsRepeatingCoupons(t *testing.T) {
+	tests := []struct {
+		name   string
+		start  time.Time
+		months int
+		want   time.Time
+	}{
+		{"mid month", date(2026, 1, 15), 3, date(2026, 4, 15)},
+		{"month end clamps", date(2026, 1, 31), 1, date(2026, 2, 28)},
+		{"year boundary", date(2026, 11, 30), 3, date(2027, 2, 28)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coupon := types.Coupon{ID: "rep", PercentOff: 10, Duration: types.CouponDurationRepeating, DurationInMonths: tt.months}
+			discount, err := NewDiscount(coupon, "SPRING", tt.start)
+			if err != nil {
+				t.Fatalf("NewDiscount() error = %v", err)
+			}
+			if discount.EndsAt == nil || !discount.EndsAt.Equal(tt.want) {
+				t.Errorf("EndsAt = %v, want %s", discount.EndsAt, tt.want)
+			}
+		})
+	}
+
+	forever, err := NewDiscount(types.Coupon{ID: "f", PercentOff: 10, Duration: types.CouponDurationForever}, "", date(2026, 1, 1))
+	if err != nil || forever.EndsAt != nil {
+		t.Errorf("NewDiscount(forever) = %+v, %v, want no end", forever, err)
+	}
+	if _, err := NewDiscount(types.Coupon{ID: "bad", Duration: types.CouponDurationOnce}, "", date(2026, 1, 1)); err == nil {
+		t.Error("NewDiscount(no discount) error = nil")
+	}
+}
+
+func TestInvoiceDiscount(t *testing.T) {
+	start := date(2026, 1, 1)
+	endsAt := date(2026, 4, 1)
+	discount := func(duration types.CouponDuration) *types.Discount {
+		d := &types.Discount{
+			Coupon:    types.Coupon{ID: "c", PercentOff: 10, Duration: duration},
+			StartedAt: start,
+		}
+		if duration == types.CouponDurationRepeating {
+			d.Coupon.DurationInMonths = 3
+			d.EndsAt = &endsAt
+		}
+		return d
+	}
+
+	tests := []struct {
+		name        string
+		discount    *types.Discount
+		periodStart time.Time
+		first       bool
+		want        int64
+	}{
+		{"nil discount", nil, start, true, 0},
+		{"period before the discount", discount(types.CouponDurationForever), date(2025, 12, 1), false, 0},
+		{"once on the first invoice", discount(types.CouponDurationOnce), start, true, 100},
+		{"once on a later invoice", discount(types.CouponDurationOnce), date(2026, 2, 1), false, 0},
+		{"repeating inside the window", discount(types.CouponDurationRepeating), date(2026, 3, 1), false, 100},
+		{"repeating just before EndsAt", discount(types.CouponDurationRepeating), endsAt.Add(-time.Second), false, 100},
+		{"repeating at EndsAt", discount(types.CouponDurationRepeating), endsAt, false, 0},
+		{"repeating after EndsAt", discount(types.CouponDurationRepeating), date(2026, 5, 1), false, 0},
+		{"forever years later", discount(types.CouponDurationForever), date(2030, 1, 1), false, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := InvoiceDiscount(tt.discount, 1000, "usd", tt.periodStart, tt.first)
+			if err != nil {
+				t.Fatalf("InvoiceDiscount() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("InvoiceDiscount() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckPromotionCode(t *testing.T) {
+	now := date(2026, 6, 1)
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	code := func(edit func(*types.PromotionCode)) *types.PromotionCode {
+		c := &types.PromotionCode{ID: "promo_1", Code: "SPRING", Active: true}
+		if edit != nil {
+			edit(c)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name string
+		code *types.PromotionCode
+		tier types.SubscriptionTier
+		want string
+	}{
+		{"valid", code(nil), types.SubscriptionTierPro, ""},
+		{"not found", nil, types.SubscriptionTierPro, types.PromotionCodeNotFound},
+		{"inactive", code(func(c *types.PromotionCode) { c.Active = false }), types.SubscriptionTierPro, types.PromotionCodeInactive},
+		{"expired", code(func(c *types.PromotionCode) { c.ExpiresAt = &past }), types.SubscriptionTierPro, types.PromotionCodeExpired},
+		{"expires exactly now", code(func(c *types.PromotionCode) { c.ExpiresAt = &now }), types.SubscriptionTierPro, types.PromotionCodeExpired},
+		{"not yet expired", code(func(c *types.PromotionCode) { c.ExpiresAt = &future }), types.SubscriptionTierPro, ""},
+		{"fully redeemed", code(func(c *types.PromotionCode) { c.MaxRedemptions, c.TimesRedeemed = 5, 5 }), types.SubscriptionTierPro, types.PromotionCodeFullyRedeemed},
+		{"redemptions left", code(func(c *types.PromotionCode) { c.MaxRedemptions, c.TimesRedeemed = 5, 4 }), types.SubscriptionTierPro, ""},
+		{"unlimited redemptions", code(func(c *types.PromotionCode) { c.TimesRedeemed = 1000 }), types.SubscriptionTierPro, ""},
+		{"tier not eligible", code(func(c *types.PromotionCode) { c.Tiers = []types.SubscriptionTier{types.SubscriptionTierBasic} }), types.SubscriptionTierPro, types.PromotionCodeTierNotEligible},
+		{"tier eligible", code(func(c *types.PromotionCode) { c.Tiers = []types.SubscriptionTier{types.SubscriptionTierPro} }), types.SubscriptionTierPro, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CheckPromotionCode(tt.code, tt.tier, now); got != tt.want {
+				t.Errorf("CheckPromotionCode() = %q, want %q", got, tt.want)
+			}
+			resp := ValidatePromotionCode(tt.code, tt.tier, now)
+			if resp.Valid != (tt.want == "") || resp.Reason != tt.want {
+				t.Errorf("ValidatePromotionCode() = %+v", resp)
+			}
+		})
+	}
+}
+
+func TestCheckTrialExtension(t *testing.T) {
+	now := date(2026, 6, 1)
+	trialEnd := now.AddDate(0, 0, 7)
+	trialing := &types.SubscriptionResponse{Status: types.SubscriptionStatusTrialing, TrialEndsAt: &trialEnd}
+
+	tests := []struct {
+		name    string
+		sub     *types.SubscriptionResponse
+		newEnd  time.Time
+		wantErr bool
+	}{
+		{"extends the trial", trialing, trialEnd.AddDate(0, 0, 7), false},
+		{"trial without an end", &types.SubscriptionResponse{Status: types.SubscriptionStatusTrialing}, now.Add(time.Hour), false},
+		{"not trialing", &types.SubscriptionResponse{Status: types.SubscriptionStatusActive}, trialEnd.AddDate(0, 0, 7), true},
+		{"end in the past", &types.SubscriptionResponse{Status: types.SubscriptionStatusTrialing}, now.Add(-time.Hour), true},
+		{"end exactly now", &types.SubscriptionResponse{Status: types.SubscriptionStatusTrialing}, now, true},
+		{"same as current end", trialing, trialEnd, true},
+		{"shortens the trial", trialing, trialEnd.AddDate(0, 0, -1), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckTrialExtension(tt.sub, types.ExtendTrialRequest{TrialEndsAt: tt.newEnd}, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckTrialExtension() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, types.ErrBadRequest) {
+				t.Errorf("error = %v, want bad request", err)
+			}
+		})
+	}
+}
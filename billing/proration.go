@@ -0,0 +1,157 @@
+package billing
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// Prices maps each tier to its price per billing period in the smallest currency unit
+type Prices map[types.SubscriptionTier]int64
+
+// Proration is the outcome of changing plans partway through a billing period
+type Proration struct {
+	// CreditCents is the unused part of the current plan
+	CreditCents int64
+	// ChargeCents is the rest of the period on the new plan
+	ChargeCents int64
+	// EffectiveAt is when the new plan starts
+	EffectiveAt time.Time
+	// CurrentPeriodEnd is the end of the first period billed on the new plan
+	CurrentPeriodEnd time.Time
+}
+
+// AmountDueCents is the charge less the credit; a negative amount is owed to the customer
+func (p Proration) AmountDueCents() int64 {
+	return p.ChargeCents - p.CreditCents
+}
+
+// Prorate computes the credit and charge of switching from currentPrice to newPrice at the given time.
+// Immediate changes keep the billing period and prorate by the seconds left in it;
+// an at outside the period is clamped to its start or end.
+// End-of-period changes start the new plan when the period ends, without proration,
+// and run it until the next renewal of the subscription's billing cycle.
+func Prorate(periodStart, periodEnd time.Time, cycle types.BillingCycle, currentPrice, newPrice int64, timing types.PlanChangeTiming, at time.Time) (Proration, error) {
+	if !periodEnd.After(periodStart) {
+		return Proration{}, fmt.Errorf("billing: period end %s is not after start %s", periodEnd, periodStart)
+	}
+	if currentPrice < 0 || newPrice < 0 {
+		return Proration{}, fmt.Errorf("billing: prices must not be negative")
+	}
+
+	switch timing {
+	case types.PlanChangeImmediate:
+		// Changes requested outside the period take effect at its nearest edge
+		if at.Before(periodStart) {
+			at = periodStart
+		} else if at.After(periodEnd) {
+			at = periodEnd
+		}
+		total := int64(periodEnd.Sub(periodStart) / time.Second)
+		remaining := int64(periodEnd.Sub(at) / time.Second)
+		return Proration{
+			CreditCents:      prorate(currentPrice, remaining, total),
+			ChargeCents:      prorate(newPrice, remaining, total),
+			EffectiveAt:      at,
+			CurrentPeriodEnd: periodEnd,
+		}, nil
+
+	case types.PlanChangeAtPeriodEnd:
+		if !cycle.Valid() {
+			return Proration{}, fmt.Errorf("billing: invalid billing cycle %+v", cycle)
+		}
+		return Proration{
+			EffectiveAt:      periodEnd,
+			CurrentPeriodEnd: renewalAfter(cycle, periodEnd),
+		}, nil
+	}
+	return Proration{}, fmt.Errorf("billing: unknown plan change timing %q", timing)
+}
+
+// Preview prices a plan change for the subscription using the tier prices
+func Preview(sub *types.SubscriptionResponse, req types.PlanChangeRequest, prices Prices, currency string, at time.Time) (*types.PlanChangePreview, error) {
+	if !sub.HasActiveSubscription() {
+		return nil, types.ErrBadRequest.WithDetails(fmt.Sprintf("subscription is %s", sub.Status))
+	}
+	if req.Tier == sub.Tier {
+		return nil, types.ErrBadRequest.WithDetails(fmt.Sprintf("subscription is already on %s", sub.Tier))
+	}
+	currentPrice, ok := prices[sub.Tier]
+	if !ok {
+		return nil, fmt.Errorf("billing: no price for tier %s", sub.Tier)
+	}
+	newPrice, ok := prices[req.Tier]
+	if !ok {
+		return nil, fmt.Errorf("billing: no price for tier %s", req.Tier)
+	}
+
+	var cycle types.BillingCycle
+	if sub.BillingCycle != nil {
+		cycle = *sub.BillingCycle
+	} else if req.Timing == types.PlanChangeAtPeriodEnd {
+		return nil, fmt.Errorf("billing: subscription %s has no billing cycle", sub.ID)
+	}
+
+	proration, err := Prorate(sub.CurrentPeriodStart, sub.CurrentPeriodEnd, cycle, currentPrice, newPrice, req.Timing, at)
+	if err != nil {
+		return nil, err
+	}
+	return &types.PlanChangePreview{
+		SubscriptionID:   sub.ID,
+		CurrentTier:      sub.Tier,
+		NewTier:          req.Tier,
+		Timing:           req.Timing,
+		EffectiveAt:      proration.EffectiveAt,
+		CreditCents:      proration.CreditCents,
+		ChargeCents:      proration.ChargeCents,
+		AmountDueCents:   proration.AmountDueCents(),
+		Currency:         currency,
+		CurrentPeriodEnd: proration.CurrentPeriodEnd,
+	}, nil
+}
+
+// prorate returns price * part / total, rounded half up
+func prorate(price, part, total int64) int64 {
+	return (price*part + total/2) / total
+}
+
+// renewalAfter returns the first renewal of the cycle after t.
+// Monthly and yearly renewals land on the anchor's day of month, clamped to the end of
+// shorter months, so a cycle anchored on Jan 31 renews on Feb 28 and then Mar 31.
+func renewalAfter(cycle types.BillingCycle, t time.Time) time.Time {
+	anchor := cycle.Anchor
+	var renewal func(n int) time.Time
+	var estimate int
+	switch cycle.Interval {
+	case types.BillingIntervalDay, types.BillingIntervalWeek:
+		days := cycle.IntervalCount
+		if cycle.Interval == types.BillingIntervalWeek {
+			days *= 7
+		}
+		renewal = func(n int) time.Time { return anchor.AddDate(0, 0, n*days) }
+		estimate = int(t.Sub(anchor)/(24*time.Hour)) / days
+	default:
+		months := cycle.IntervalCount
+		if cycle.Interval == types.BillingIntervalYear {
+			months *= 12
+		}
+		renewal = func(n int) time.Time { return addMonths(anchor, n*months, anchor.Day()) }
+		estimate = ((t.Year()-anchor.Year())*12 + int(t.Month()-anchor.Month())) / months
+	}
+
+	// The estimate is off by at most one renewal either way
+	n := max(estimate-1, 0)
+	for !renewal(n).After(t) {
+		n++
+	}
+	return renewal(n)
+}
+
+// addMonths moves t forward by months, landing on the anchor day or the last day of shorter months
+func addMonths(t time.Time, months, anchor int) time.Time {
+	year, month, _ := t.Date()
+	first := time.Date(year, month+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(anchor, last)-1)
+}
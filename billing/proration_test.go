@@ -0,0 +1,185 @@
+package billing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func every(count int, interval types.BillingInterval, anchor time.Time) types.BillingCycle {
+	return types.BillingCycle{Interval: interval, IntervalCount: count, Anchor: anchor}
+}
+
+func TestProrateImmediate(t *testing.T) {
+	start, end := date(2026, 4, 1), date(2026, 5, 1) // 30 days
+
+	tests := []struct {
+		name          string
+		at            time.Time
+		wantCredit    int64
+		wantCharge    int64
+		wantEffective time.Time
+	}{
+		{"start of period", start, 3000, 6000, start},
+		{"a third in", date(2026, 4, 11), 2000, 4000, date(2026, 4, 11)},
+		{"half way", date(2026, 4, 16), 1500, 3000, date(2026, 4, 16)},
+		{"end of period", end, 0, 0, end},
+		{"before the period", date(2026, 3, 20), 3000, 6000, start},
+		{"after the period", date(2026, 5, 10), 0, 0, end},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Prorate(start, end, every(1, types.BillingIntervalMonth, start), 3000, 6000, types.PlanChangeImmediate, tt.at)
+			if err != nil {
+				t.Fatalf("Prorate() error = %v", err)
+			}
+			if got.CreditCents != tt.wantCredit || got.ChargeCents != tt.wantCharge {
+				t.Errorf("credit, charge = %d, %d, want %d, %d", got.CreditCents, got.ChargeCents, tt.wantCredit, tt.wantCharge)
+			}
+			if !got.EffectiveAt.Equal(tt.wantEffective) || !got.CurrentPeriodEnd.Equal(end) {
+				t.Errorf("effective = %s, period end = %s, want %s, %s", got.EffectiveAt, got.CurrentPeriodEnd, tt.wantEffective, end)
+			}
+			if got.AmountDueCents() != tt.wantCharge-tt.wantCredit {
+				t.Errorf("AmountDueCents() = %d", got.AmountDueCents())
+			}
+		})
+	}
+}
+
+func TestProrateRoundsHalfUp(t *testing.T) {
+	// One second left of a three second period: 100/3 rounds down, 200/3 rounds up
+	start := date(2026, 1, 1)
+	got, err := Prorate(start, start.Add(3*time.Second), types.BillingCycle{}, 100, 200, types.PlanChangeImmediate, start.Add(2*time.Second))
+	if err != nil {
+		t.Fatalf("Prorate() error = %v", err)
+	}
+	if got.CreditCents != 33 || got.ChargeCents != 67 {
+		t.Errorf("credit, charge = %d, %d, want 33, 67", got.CreditCents, got.ChargeCents)
+	}
+}
+
+func TestProrateAtPeriodEnd(t *testing.T) {
+	const (
+		day   = types.BillingIntervalDay
+		week  = types.BillingIntervalWeek
+		month = types.BillingIntervalMonth
+		year  = types.BillingIntervalYear
+	)
+	tests := []struct {
+		name       string
+		start, end time.Time
+		cycle      types.BillingCycle
+		want       time.Time
+	}{
+		{"monthly", date(2026, 4, 15), date(2026, 5, 15), every(1, month, date(2026, 4, 15)), date(2026, 6, 15)},
+		{"anchor 31 into February", date(2026, 12, 31), date(2027, 1, 31), every(1, month, date(2026, 12, 31)), date(2027, 2, 28)},
+		{"anchor 31 out of February", date(2027, 1, 31), date(2027, 2, 28), every(1, month, date(2026, 10, 31)), date(2027, 3, 31)},
+		{"anchor 31 out of April", date(2026, 3, 31), date(2026, 4, 30), every(1, month, date(2026, 3, 31)), date(2026, 5, 31)},
+		{"anchor 30 out of February", date(2026, 1, 30), date(2026, 2, 28), every(1, month, date(2026, 1, 30)), date(2026, 3, 30)},
+		{"anchor 29 into leap February", date(2028, 1, 29), date(2028, 2, 29), every(1, month, date(2027, 12, 29)), date(2028, 3, 29)},
+		{"anchor 15 after a longer first period", date(2026, 1, 15), date(2026, 2, 28), every(1, month, date(2026, 1, 15)), date(2026, 3, 15)},
+		{"re-anchored to the 31st", date(2026, 1, 15), date(2026, 2, 28), every(1, month, date(2026, 1, 31)), date(2026, 3, 31)},
+		{"quarterly anchor 31", date(2026, 10, 31), date(2027, 1, 31), every(3, month, date(2026, 10, 31)), date(2027, 4, 30)},
+		{"yearly from leap day", date(2024, 2, 29), date(2025, 2, 28), every(1, year, date(2024, 2, 29)), date(2026, 2, 28)},
+		{"leap year anniversary", date(2027, 2, 28), date(2028, 2, 29), every(1, year, date(2024, 2, 29)), date(2029, 2, 28)},
+		{"weekly", date(2026, 4, 1), date(2026, 4, 8), every(1, week, date(2026, 4, 1)), date(2026, 4, 15)},
+		{"every two weeks", date(2026, 4, 1), date(2026, 4, 15), every(2, week, date(2026, 3, 4)), date(2026, 4, 29)},
+		{"daily", date(2026, 4, 1), date(2026, 4, 2), every(1, day, date(2026, 1, 1)), date(2026, 4, 3)},
+		{"anchor after the period", date(2026, 4, 1), date(2026, 4, 20), every(1, month, date(2026, 5, 1)), date(2026, 5, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Prorate(tt.start, tt.end, tt.cycle, 3000, 6000, types.PlanChangeAtPeriodEnd, tt.start.AddDate(0, 0, 3))
+			if err != nil {
+				t.Fatalf("Prorate() error = %v", err)
+			}
+			if got.CreditCents != 0 || got.ChargeCents != 0 {
+				t.Errorf("credit, charge = %d, %d, want no proration", got.CreditCents, got.ChargeCents)
+			}
+			if !got.EffectiveAt.Equal(tt.end) {
+				t.Errorf("EffectiveAt = %s, want %s", got.EffectiveAt, tt.end)
+			}
+			if !got.CurrentPeriodEnd.Equal(tt.want) {
+				t.Errorf("CurrentPeriodEnd = %s, want %s", got.CurrentPeriodEnd, tt.want)
+			}
+		})
+	}
+}
+
+func TestProrateRejectsInvalidInput(t *testing.T) {
+	start, end := date(2026, 4, 1), date(2026, 5, 1)
+	monthly := every(1, types.BillingIntervalMonth, start)
+	tests := []struct {
+		name                   string
+		start, end             time.Time
+		cycle                  types.BillingCycle
+		currentPrice, newPrice int64
+		timing                 types.PlanChangeTiming
+	}{
+		{"empty period", start, start, monthly, 100, 200, types.PlanChangeImmediate},
+		{"negative price", start, end, monthly, -1, 200, types.PlanChangeImmediate},
+		{"unknown timing", start, end, monthly, 100, 200, types.PlanChangeTiming("later")},
+		{"no billing cycle", start, end, types.BillingCycle{}, 100, 200, types.PlanChangeAtPeriodEnd},
+		{"unknown interval", start, end, every(1, "fortnight", start), 100, 200, types.PlanChangeAtPeriodEnd},
+		{"zero interval count", start, end, every(0, types.BillingIntervalMonth, start), 100, 200, types.PlanChangeAtPeriodEnd},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Prorate(tt.start, tt.end, tt.cycle, tt.currentPrice, tt.newPrice, tt.timing, start); err == nil {
+				t.Error("Prorate() error = nil")
+			}
+		})
+	}
+}
+
+func TestPreview(t *testing.T) {
+	prices := Prices{types.SubscriptionTierBasic: 3000, types.SubscriptionTierPro: 6000}
+	newSub := func() *types.SubscriptionResponse {
+		return &types.SubscriptionResponse{
+			ID:                 "sub_1",
+			Tier:               types.SubscriptionTierBasic,
+			Status:             types.SubscriptionStatusActive,
+			CurrentPeriodStart: date(2026, 1, 15),
+			CurrentPeriodEnd:   date(2026, 2, 28),
+			BillingCycle:       &types.BillingCycle{Interval: types.BillingIntervalMonth, IntervalCount: 1, Anchor: date(2026, 1, 15)},
+		}
+	}
+
+	preview, err := Preview(newSub(), types.PlanChangeRequest{Tier: types.SubscriptionTierPro, Timing: types.PlanChangeAtPeriodEnd}, prices, "usd", date(2026, 2, 1))
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+	if !preview.EffectiveAt.Equal(date(2026, 2, 28)) || !preview.CurrentPeriodEnd.Equal(date(2026, 3, 15)) || preview.AmountDueCents != 0 {
+		t.Errorf("Preview() = %+v", preview)
+	}
+
+	// Immediate changes only need the current period
+	sub := newSub()
+	sub.BillingCycle = nil
+	sub.CurrentPeriodEnd = date(2026, 2, 15)
+	preview, err = Preview(sub, types.PlanChangeRequest{Tier: types.SubscriptionTierPro, Timing: types.PlanChangeImmediate}, prices, "usd", date(2026, 1, 15))
+	if err != nil {
+		t.Fatalf("Preview(immediate) error = %v", err)
+	}
+	if preview.AmountDueCents != 3000 || preview.Currency != "usd" {
+		t.Errorf("Preview(immediate) = %+v", preview)
+	}
+
+	if _, err := Preview(sub, types.PlanChangeRequest{Tier: types.SubscriptionTierPro, Timing: types.PlanChangeAtPeriodEnd}, prices, "usd", date(2026, 2, 1)); err == nil {
+		t.Error("Preview() without a billing cycle error = nil")
+	}
+	sub = newSub()
+	sub.Status = types.SubscriptionStatusCanceled
+	if _, err := Preview(sub, types.PlanChangeRequest{Tier: types.SubscriptionTierPro, Timing: types.PlanChangeImmediate}, prices, "usd", date(2026, 2, 1)); !errors.Is(err, types.ErrBadRequest) {
+		t.Errorf("Preview(canceled) error = %v", err)
+	}
+	if _, err := Preview(newSub(), types.PlanChangeRequest{Tier: types.SubscriptionTierEnterprise, Timing: types.PlanChangeImmediate}, prices, "usd", date(2026, 2, 1)); err == nil {
+		t.Error("Preview() without a price error = nil")
+	}
+}
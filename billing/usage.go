@@ -0,0 +1,62 @@
+package billing
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// usageDescriptions names the phone usage types on invoices
+var usageDescriptions = map[string]string{
+	types.PhoneUsageTypeVoiceInbound:  "Inbound calls",
+	types.PhoneUsageTypeVoiceOutbound: "Outbound calls",
+	types.PhoneUsageTypeSMSInbound:    "Inbound SMS",
+	types.PhoneUsageTypeSMSOutbound:   "Outbound SMS",
+	types.PhoneUsageTypeMMSInbound:    "Inbound MMS",
+	types.PhoneUsageTypeMMSOutbound:   "Outbound MMS",
+}
+
+// UsageLineItems aggregates phone usage within [periodStart, periodEnd) into one metered line per usage type.
+// Each line's quantity is the number of usage records and its amount the sum of their CostCents.
+// Lines are ordered by usage type; types without cost in the period are omitted.
+func UsageLineItems(usage []types.PhoneUsage, currency string, periodStart, periodEnd time.Time) []types.InvoiceLineItem {
+	byType := map[string]*types.InvoiceLineItem{}
+	for _, record := range usage {
+		if record.CreatedAt.Before(periodStart) || !record.CreatedAt.Before(periodEnd) {
+			continue
+		}
+		item, ok := byType[record.UsageType]
+		if !ok {
+			item = &types.InvoiceLineItem{
+				Type:        types.InvoiceLineMetered,
+				Description: usageDescription(record.UsageType),
+				Currency:    strings.ToLower(currency),
+				UsageType:   record.UsageType,
+				PeriodStart: periodStart,
+				PeriodEnd:   periodEnd,
+			}
+			byType[record.UsageType] = item
+		}
+		item.Quantity++
+		item.AmountCents += int64(record.CostCents)
+	}
+
+	items := make([]types.InvoiceLineItem, 0, len(byType))
+	for _, item := range byType {
+		if item.AmountCents == 0 {
+			continue
+		}
+		items = append(items, *item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].UsageType < items[j].UsageType })
+	return items
+}
+
+func usageDescription(usageType string) string {
+	if description, ok := usageDescriptions[usageType]; ok {
+		return description
+	}
+	return usageType
+}
@@ -0,0 +1,94 @@
+package billing
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+func usage(usageType string, costCents int, at time.Time) types.PhoneUsage {
+	return types.PhoneUsage{UsageType: usageType, CostCents: costCents, CreatedAt: at}
+}
+
+func TestUsageLineItems(t *testing.T) {
+	start, end := date(2026, 4, 1), date(2026, 5, 1)
+	records := []types.PhoneUsage{
+		usage(types.PhoneUsageTypeVoiceOutbound, 13, start),
+		usage(types.PhoneUsageTypeSMSOutbound, 1, start.Add(time.Hour)),
+		usage(types.PhoneUsageTypeVoiceOutbound, 7, date(2026, 4, 15)),
+		usage(types.PhoneUsageTypeSMSOutbound, 1, end.Add(-time.Nanosecond)),
+		usage(types.PhoneUsageTypeSMSOutbound, 1, date(2026, 4, 20)),
+		// Free inbound messages are counted but not billed
+		usage(types.PhoneUsageTypeSMSInbound, 0, date(2026, 4, 2)),
+		usage("fax", 40, date(2026, 4, 3)),
+		// Outside the period
+		usage(types.PhoneUsageTypeVoiceOutbound, 500, start.Add(-time.Nanosecond)),
+		usage(types.PhoneUsageTypeVoiceOutbound, 500, end),
+	}
+
+	got := UsageLineItems(records, "USD", start, end)
+	want := []types.InvoiceLineItem{
+		{Description: "fax", Quantity: 1, AmountCents: 40, UsageType: "fax"},
+		{Description: "Outbound SMS", Quantity: 3, AmountCents: 3, UsageType: types.PhoneUsageTypeSMSOutbound},
+		{Description: "Outbound calls", Quantity: 2, AmountCents: 20, UsageType: types.PhoneUsageTypeVoiceOutbound},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("UsageLineItems() = %+v, want %d lines", got, len(want))
+	}
+	for i := range want {
+		want[i].Type = types.InvoiceLineMetered
+		want[i].Currency = "usd"
+		want[i].PeriodStart = start
+		want[i].PeriodEnd = end
+		if got[i] != want[i] {
+			t.Errorf("line %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestUsageLineItemsSumsCentsExactly(t *testing.T) {
+	// A third of a cent per message would drift if amounts were converted to dollars
+	start, end := date(2026, 4, 1), date(2026, 5, 1)
+	records := make([]types.PhoneUsage, 0, 3000)
+	for i := range 3000 {
+		records = append(records, usage(types.PhoneUsageTypeSMSOutbound, 1+i%2, start.Add(time.Duration(i)*time.Minute)))
+	}
+	got := UsageLineItems(records, "usd", start, end)
+	if len(got) != 1 || got[0].Quantity != 3000 || got[0].AmountCents != 4500 {
+		t.Errorf("UsageLineItems() = %+v, want 3000 messages for 4500 cents", got)
+	}
+}
+
+func TestUsageLineItemsEmptyPeriod(t *testing.T) {
+	start, end := date(2026, 4, 1), date(2026, 5, 1)
+	tests := []struct {
+		name    string
+		records []types.PhoneUsage
+	}{
+		{"no usage", nil},
+		{"only free usage", []types.PhoneUsage{usage(types.PhoneUsageTypeSMSInbound, 0, start)}},
+		{"usage in other periods", []types.PhoneUsage{
+			usage(types.PhoneUsageTypeVoiceOutbound, 10, date(2026, 3, 31)),
+			usage(types.PhoneUsageTypeVoiceOutbound, 10, end),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := UsageLineItems(tt.records, "usd", start, end)
+			data, err := json.Marshal(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Encoded as an empty list rather than null
+			if string(data) != "[]" {
+				t.Errorf("UsageLineItems() = %s, want []", data)
+			}
+		})
+	}
+
+	if got := UsageLineItems([]types.PhoneUsage{usage(types.PhoneUsageTypeSMSOutbound, 1, start)}, "usd", start, start); len(got) != 0 {
+		t.Errorf("UsageLineItems() over a zero length period = %+v", got)
+	}
+}
@@ -11,9 +11,14 @@ import (
 type PhoneServiceClient interface {
 	// Phone number management
 	ProvisionPhoneNumber(ctx context.Context, req types.PhoneProvisionRequest) (*types.PhoneProvisionResponse, error)
+	// ReleasePhoneNumber quarantines the number when the service has a release cooldown, and releases it otherwise
 	ReleasePhoneNumber(ctx context.Context, phoneNumberID uuid.UUID) error
+	// ReclaimPhoneNumber reactivates a quarantined number before its quarantine ends
+	ReclaimPhoneNumber(ctx context.Context, phoneNumberID uuid.UUID) (*types.PhoneNumber, error)
 	GetUserPhoneNumbers(ctx context.Context, userID string) ([]types.PhoneNumber, error)
 	GetPhoneNumberUsage(ctx context.Context, phoneNumberID uuid.UUID) ([]types.PhoneUsage, error)
+	// GetPhoneNumberHistory returns the status changes of the number, oldest first
+	GetPhoneNumberHistory(ctx context.Context, phoneNumberID uuid.UUID) ([]types.PhoneNumberStatusChange, error)
 	
 	// Phone number configuration
 	UpdatePhoneNumberConfiguration(ctx context.Context, phoneNumberID uuid.UUID, config map[string]interface{}) error
@@ -29,6 +34,13 @@ type PhoneServiceClient interface {
 type PhoneProviderServiceClient interface {
 	// Simple phone number purchase
 	PurchasePhoneNumber(ctx context.Context, req types.PurchasePhoneNumberRequest) (*types.PurchasePhoneNumberResponse, error)
+
+	// Number selection: search, reserve one of the results, then purchase it by ReservationID.
+	// Reserving a number another user holds fails with types.ErrConflict, and purchasing
+	// an unknown or expired reservation fails with types.ErrNotFound.
+	SearchAvailableNumbers(ctx context.Context, req types.AvailableNumberSearchRequest) (*types.AvailableNumberSearchResponse, error)
+	ReservePhoneNumber(ctx context.Context, req types.ReservePhoneNumberRequest) (*types.PhoneNumberReservation, error)
+	CancelReservation(ctx context.Context, reservationID uuid.UUID) error
 	GetServiceHealth(ctx context.Context) (*types.HealthResponse, error)
 }
 
@@ -37,4 +49,17 @@ type PhoneWebhookHandler interface {
 	HandleVoiceWebhook(ctx context.Context, data map[string]string) error
 	HandleSMSWebhook(ctx context.Context, data map[string]string) error
 	HandleStatusWebhook(ctx context.Context, data map[string]string) error
-}
\ No newline at end of file
+}
+
+// TwiMLDocument is a TwiML response rendered on demand, such as twilio.VoiceResponse
+type TwiMLDocument interface {
+	TwiML() ([]byte, error)
+}
+
+// PhoneTwiMLWebhookHandler is the PhoneWebhookHandler variant whose voice and SMS handlers answer Twilio with TwiML.
+// A nil document tells Twilio to do nothing further.
+type PhoneTwiMLWebhookHandler interface {
+	HandleVoiceWebhook(ctx context.Context, data map[string]string) (TwiMLDocument, error)
+	HandleSMSWebhook(ctx context.Context, data map[string]string) (TwiMLDocument, error)
+	HandleStatusWebhook(ctx context.Context, data map[string]string) error
+}
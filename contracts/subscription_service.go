@@ -2,7 +2,9 @@ package contracts
 
 import (
 	"context"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jonnyt98/atlas-shared/types"
 )
 
@@ -10,13 +12,27 @@ import (
 type SubscriptionServiceClient interface {
 	// Checkout and payment
 	CreateCheckoutSession(ctx context.Context, req types.CheckoutSessionRequest) (*types.CheckoutSessionResponse, error)
+	ValidatePromotionCode(ctx context.Context, req types.ValidatePromotionCodeRequest) (*types.ValidatePromotionCodeResponse, error)
 	
 	// Subscription CRUD operations
 	CreateSubscription(ctx context.Context, req types.SubscriptionCreateRequest) (*types.SubscriptionResponse, error)
 	GetSubscriptionByStripeID(ctx context.Context, stripeSubscriptionID string) (*types.SubscriptionResponse, error)
 	GetSubscriptionByUserID(ctx context.Context, userID string) (*types.SubscriptionResponse, error)
+	GetSubscriptionByOrgID(ctx context.Context, orgID uuid.UUID) (*types.SubscriptionResponse, error)
 	UpdateSubscriptionByStripeID(ctx context.Context, stripeSubscriptionID string, req types.SubscriptionUpdateRequest) (*types.SubscriptionResponse, error)
 	CancelSubscription(ctx context.Context, subscriptionID string) (*types.SubscriptionResponse, error)
+	UpdateSeats(ctx context.Context, subscriptionID string, req types.SeatUpdateRequest) (*types.SubscriptionResponse, error)
+	ExtendTrial(ctx context.Context, subscriptionID string, req types.ExtendTrialRequest) (*types.SubscriptionResponse, error)
+	
+	// Plan changes
+	PreviewPlanChange(ctx context.Context, subscriptionID string, req types.PlanChangeRequest) (*types.PlanChangePreview, error)
+	ChangePlan(ctx context.Context, subscriptionID string, req types.PlanChangeRequest) (*types.PlanChangeResponse, error)
+	
+	// Billing history and payment methods
+	ListInvoices(ctx context.Context, userID string, query types.InvoiceListQuery) (*types.Page[types.Invoice], error)
+	GetInvoice(ctx context.Context, invoiceID string) (*types.Invoice, error)
+	GetUpcomingInvoice(ctx context.Context, userID string) (*types.Invoice, error)
+	UpdateDefaultPaymentMethod(ctx context.Context, userID string, req types.UpdatePaymentMethodRequest) (*types.PaymentMethod, error)
 	
 	// Stripe webhook handling
 	HandleStripeWebhook(ctx context.Context, payload []byte, signature string) error
@@ -61,6 +77,7 @@ type EmailServiceClient interface {
 	SendPasswordResetEmail(ctx context.Context, userID, email, token string) error
 	SendSubscriptionConfirmationEmail(ctx context.Context, userID, email string, subscription types.SubscriptionResponse) error
 	SendSubscriptionCancelationEmail(ctx context.Context, userID, email string) error
+	SendPaymentReminderEmail(ctx context.Context, userID, email string, graceEndsAt time.Time) error
 	
 	// Health check
 	Health(ctx context.Context) error
@@ -0,0 +1,24 @@
+package contracts
+
+// User service HTTP routes shared by clients and servers.
+// Each route is a "METHOD /path" pattern compatible with net/http.ServeMux.
+const (
+	UserRouteCreate                  = "POST /users"
+	UserRouteList                    = "GET /users"
+	UserRouteGetByID                 = "GET /users/{id}"
+	UserRouteGetByEmail              = "GET /users/by-email"
+	UserRouteGetByGoogleID           = "GET /users/by-google-id"
+	UserRouteUpdate                  = "PATCH /users/{id}"
+	UserRouteDelete                  = "DELETE /users/{id}"
+	UserRouteUpdateEmailVerification = "PUT /users/{id}/email-verification"
+	UserRouteVerifyEmail             = "POST /users/verify-email"
+	UserRouteResendVerification      = "POST /users/{id}/resend-verification"
+	UserRouteAuthenticate            = "POST /users/authenticate"
+	UserRouteLinkGoogleAccount       = "POST /users/{id}/google"
+	UserRouteUpdatePassword          = "PUT /users/{id}/password"
+	UserRouteUpdateSubscription      = "PUT /users/{id}/subscription"
+	UserRouteGetSubscription         = "GET /users/{id}/subscription"
+	UserRouteCancelSubscription      = "DELETE /users/{id}/subscription"
+	UserRouteExists                  = "GET /users/exists"
+	UserRouteHealth                  = "GET /health"
+)
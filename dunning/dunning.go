@@ -0,0 +1,251 @@
+package dunning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jonnyt98/atlas-shared/contracts"
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// day is the unit of the schedule
+const day = 24 * time.Hour
+
+// Schedule configures reminders and the grace period of a past_due subscription
+type Schedule struct {
+	// ReminderDays are the days after the subscription became past_due on which a reminder is sent
+	ReminderDays []int `json:"reminder_days"`
+	// GraceDays is how long entitlements stay active; the subscription is canceled when it ends
+	GraceDays int `json:"grace_days"`
+}
+
+// Validate checks that the grace period is positive and every reminder falls inside it
+func (s Schedule) Validate() error {
+	if s.GraceDays <= 0 {
+		return fmt.Errorf("dunning: grace period must be at least one day, got %d", s.GraceDays)
+	}
+	for _, d := range s.ReminderDays {
+		if d < 0 || d >= s.GraceDays {
+			return fmt.Errorf("dunning: reminder on day %d is outside the %d day grace period", d, s.GraceDays)
+		}
+	}
+	return nil
+}
+
+// Manager runs past_due subscriptions through the dunning schedule.
+// Start opens a case when a subscription becomes past_due and Resolve closes
+// it when payment succeeds. Tick, called periodically or through Run, sends
+// the reminders that are due and cancels subscriptions whose grace period ended.
+type Manager struct {
+	schedule      Schedule
+	store         Store
+	emails        contracts.EmailServiceClient
+	subscriptions contracts.SubscriptionServiceClient
+
+	// Now returns the current time; defaults to time.Now
+	Now func() time.Time
+}
+
+// NewManager creates a manager for a validated schedule
+func NewManager(schedule Schedule, store Store, emails contracts.EmailServiceClient, subscriptions contracts.SubscriptionServiceClient) (*Manager, error) {
+	if err := schedule.Validate(); err != nil {
+		return nil, err
+	}
+	days := append([]int(nil), schedule.ReminderDays...)
+	sort.Ints(days)
+	schedule.ReminderDays = days
+
+	return &Manager{
+		schedule:      schedule,
+		store:         store,
+		emails:        emails,
+		subscriptions: subscriptions,
+	}, nil
+}
+
+// Start opens a dunning case for a subscription that became past_due.
+// Starting a subscription that already has an open case keeps the original schedule.
+func (m *Manager) Start(ctx context.Context, sub *types.SubscriptionResponse, email string) error {
+	_, err := m.store.Get(ctx, sub.ID)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, ErrCaseNotFound) {
+		return fmt.Errorf("dunning: load case: %w", err)
+	}
+
+	c := &Case{
+		SubscriptionID: sub.ID,
+		UserID:         sub.UserID,
+		OrgID:          sub.OrgID,
+		Email:          email,
+		PastDueSince:   m.now(),
+	}
+	if err := m.store.Put(ctx, c); err != nil {
+		return fmt.Errorf("dunning: store case: %w", err)
+	}
+	return nil
+}
+
+// Resolve closes the dunning case of a subscription whose payment succeeded
+func (m *Manager) Resolve(ctx context.Context, subscriptionID string) error {
+	if err := m.store.Delete(ctx, subscriptionID); err != nil {
+		return fmt.Errorf("dunning: delete case: %w", err)
+	}
+	return nil
+}
+
+// GraceEndsAt returns when the case's grace period ends and the subscription is canceled
+func (m *Manager) GraceEndsAt(c *Case) time.Time {
+	return c.PastDueSince.Add(time.Duration(m.schedule.GraceDays) * day)
+}
+
+// InGrace reports whether the subscription has an open case whose grace period has not ended
+func (m *Manager) InGrace(ctx context.Context, subscriptionID string) (bool, error) {
+	c, err := m.store.Get(ctx, subscriptionID)
+	if errors.Is(err, ErrCaseNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("dunning: load case: %w", err)
+	}
+	return m.now().Before(m.GraceEndsAt(c)), nil
+}
+
+// Grace implements entitlements.GraceFunc so past_due subscriptions keep their plan during the grace period.
+// Entitlement checks carry no context, so the store is read with a background context
+// and lookup failures count as outside the grace period.
+func (m *Manager) Grace(sub *types.SubscriptionResponse) bool {
+	inGrace, err := m.InGrace(context.Background(), sub.ID)
+	return err == nil && inGrace
+}
+
+// Tick advances every open case to the current time and joins the errors of those that fail.
+// When several reminders became due since the last tick only one is sent.
+func (m *Manager) Tick(ctx context.Context) error {
+	cases, err := m.store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("dunning: list cases: %w", err)
+	}
+	var errs []error
+	for _, c := range cases {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := m.advance(ctx, c); err != nil {
+			errs = append(errs, fmt.Errorf("dunning: subscription %s: %w", c.SubscriptionID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Run calls Tick every interval until ctx is done, reporting failures to onError
+func (m *Manager) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.Tick(ctx); err != nil && onError != nil && !errors.Is(err, context.Canceled) {
+				onError(err)
+			}
+		}
+	}
+}
+
+func (m *Manager) advance(ctx context.Context, c *Case) error {
+	now := m.now()
+	graceEndsAt := m.GraceEndsAt(c)
+
+	if !now.Before(graceEndsAt) {
+		return m.cancel(ctx, c)
+	}
+
+	due := c.RemindersSent
+	for due < len(m.schedule.ReminderDays) && !now.Before(c.PastDueSince.Add(time.Duration(m.schedule.ReminderDays[due])*day)) {
+		due++
+	}
+	if due == c.RemindersSent {
+		return nil
+	}
+
+	if err := m.emails.SendPaymentReminderEmail(ctx, c.UserID, c.Email, graceEndsAt); err != nil {
+		return fmt.Errorf("send reminder: %w", err)
+	}
+	c.RemindersSent = due
+	if err := m.store.Put(ctx, c); err != nil {
+		return fmt.Errorf("store case: %w", err)
+	}
+	return nil
+}
+
+// cancel ends a case whose grace period is over. It re-reads the subscription first, so a payment
+// whose Resolve was missed closes the case instead of canceling a customer who paid.
+// Each step is safe to retry: a subscription that is already canceled is not canceled again,
+// and the case is only deleted once the cancelation email has been sent.
+func (m *Manager) cancel(ctx context.Context, c *Case) error {
+	sub, err := m.currentSubscription(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case sub == nil:
+		return m.close(ctx, c)
+	case sub.Status == types.SubscriptionStatusPastDue || sub.Status == types.SubscriptionStatusUnpaid:
+		if _, err := m.subscriptions.CancelSubscription(ctx, c.SubscriptionID); err != nil {
+			return fmt.Errorf("cancel subscription: %w", err)
+		}
+	case sub.Status != types.SubscriptionStatusCanceled:
+		// Paid since the case was opened
+		return m.close(ctx, c)
+	}
+
+	if err := m.emails.SendSubscriptionCancelationEmail(ctx, c.UserID, c.Email); err != nil {
+		return fmt.Errorf("send cancelation: %w", err)
+	}
+	return m.close(ctx, c)
+}
+
+// currentSubscription loads the case's subscription, or nil if it no longer exists or was replaced
+func (m *Manager) currentSubscription(ctx context.Context, c *Case) (*types.SubscriptionResponse, error) {
+	var (
+		sub *types.SubscriptionResponse
+		err error
+	)
+	if c.OrgID != nil {
+		sub, err = m.subscriptions.GetSubscriptionByOrgID(ctx, *c.OrgID)
+	} else {
+		sub, err = m.subscriptions.GetSubscriptionByUserID(ctx, c.UserID)
+	}
+	if errors.Is(err, types.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load subscription: %w", err)
+	}
+	if sub.ID != c.SubscriptionID {
+		return nil, nil
+	}
+	return sub, nil
+}
+
+func (m *Manager) close(ctx context.Context, c *Case) error {
+	if err := m.store.Delete(ctx, c.SubscriptionID); err != nil {
+		return fmt.Errorf("delete case: %w", err)
+	}
+	return nil
+}
+
+func (m *Manager) now() time.Time {
+	if m.Now != nil {
+		return m.Now()
+	}
+	return time.Now()
+}
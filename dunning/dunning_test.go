@@ -0,0 +1,229 @@
+package dunning
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jonnyt98/atlas-shared/contracts"
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// fakeSubscriptions serves one subscription and records cancelations
+type fakeSubscriptions struct {
+	contracts.SubscriptionServiceClient
+
+	sub       *types.SubscriptionResponse
+	cancelErr error
+	canceled  int
+}
+
+func (f *fakeSubscriptions) GetSubscriptionByUserID(ctx context.Context, userID string) (*types.SubscriptionResponse, error) {
+	if f.sub == nil || f.sub.UserID != userID {
+		return nil, types.ErrNotFound
+	}
+	copied := *f.sub
+	return &copied, nil
+}
+
+func (f *fakeSubscriptions) GetSubscriptionByOrgID(ctx context.Context, orgID uuid.UUID) (*types.SubscriptionResponse, error) {
+	if f.sub == nil || f.sub.OrgID == nil || *f.sub.OrgID != orgID {
+		return nil, types.ErrNotFound
+	}
+	copied := *f.sub
+	return &copied, nil
+}
+
+func (f *fakeSubscriptions) CancelSubscription(ctx context.Context, subscriptionID string) (*types.SubscriptionResponse, error) {
+	if f.cancelErr != nil {
+		return nil, f.cancelErr
+	}
+	f.canceled++
+	f.sub.Status = types.SubscriptionStatusCanceled
+	copied := *f.sub
+	return &copied, nil
+}
+
+// fakeEmails records sent emails and fails cancelation emails while cancelErr is set
+type fakeEmails struct {
+	contracts.EmailServiceClient
+
+	reminders    int
+	cancelations int
+	cancelErr    error
+}
+
+func (f *fakeEmails) SendPaymentReminderEmail(ctx context.Context, userID, email string, graceEndsAt time.Time) error {
+	f.reminders++
+	return nil
+}
+
+func (f *fakeEmails) SendSubscriptionCancelationEmail(ctx context.Context, userID, email string) error {
+	if f.cancelErr != nil {
+		return f.cancelErr
+	}
+	f.cancelations++
+	return nil
+}
+
+// failingDeleteStore fails Delete while err is set
+type failingDeleteStore struct {
+	*MemoryStore
+	err error
+}
+
+func (s *failingDeleteStore) Delete(ctx context.Context, subscriptionID string) error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.MemoryStore.Delete(ctx, subscriptionID)
+}
+
+func newTestManager(t *testing.T, store Store, sub *types.SubscriptionResponse) (*Manager, *fakeSubscriptions, *fakeEmails, *time.Time) {
+	t.Helper()
+	subs := &fakeSubscriptions{sub: sub}
+	emails := &fakeEmails{}
+	m, err := NewManager(Schedule{ReminderDays: []int{0, 3}, GraceDays: 7}, store, emails, subs)
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	m.Now = func() time.Time { return now }
+	if err := m.Start(context.Background(), sub, "a@example.com"); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	return m, subs, emails, &now
+}
+
+func pastDue(userID string) *types.SubscriptionResponse {
+	return &types.SubscriptionResponse{ID: "sub_1", UserID: userID, Status: types.SubscriptionStatusPastDue}
+}
+
+func openCases(t *testing.T, store Store) int {
+	t.Helper()
+	cases, err := store.List(context.Background())
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	return len(cases)
+}
+
+func TestTickSendsRemindersThenCancels(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	m, subs, emails, now := newTestManager(t, store, pastDue("u1"))
+
+	if err := m.Tick(ctx); err != nil || emails.reminders != 1 {
+		t.Fatalf("Tick(day 0) = %v, reminders = %d, want 1", err, emails.reminders)
+	}
+	*now = now.Add(3 * day)
+	if err := m.Tick(ctx); err != nil || emails.reminders != 2 {
+		t.Fatalf("Tick(day 3) = %v, reminders = %d, want 2", err, emails.reminders)
+	}
+	*now = now.Add(4 * day)
+	if err := m.Tick(ctx); err != nil {
+		t.Fatalf("Tick(day 7) error = %v", err)
+	}
+	if subs.canceled != 1 || emails.cancelations != 1 || openCases(t, store) != 0 {
+		t.Errorf("canceled = %d, cancelation emails = %d, open cases = %d, want 1, 1, 0", subs.canceled, emails.cancelations, openCases(t, store))
+	}
+}
+
+func TestCancelChecksCurrentStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     types.SubscriptionStatus
+		wantCancel int
+		wantEmail  int
+	}{
+		{"past_due", types.SubscriptionStatusPastDue, 1, 1},
+		{"unpaid", types.SubscriptionStatusUnpaid, 1, 1},
+		{"paid since", types.SubscriptionStatusActive, 0, 0},
+		{"already canceled", types.SubscriptionStatusCanceled, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryStore()
+			m, subs, emails, now := newTestManager(t, store, pastDue("u1"))
+			subs.sub.Status = tt.status
+			*now = now.Add(7 * day)
+
+			if err := m.Tick(context.Background()); err != nil {
+				t.Fatalf("Tick() error = %v", err)
+			}
+			if subs.canceled != tt.wantCancel || emails.cancelations != tt.wantEmail {
+				t.Errorf("canceled = %d, emails = %d, want %d, %d", subs.canceled, emails.cancelations, tt.wantCancel, tt.wantEmail)
+			}
+			if n := openCases(t, store); n != 0 {
+				t.Errorf("open cases = %d, want 0", n)
+			}
+		})
+	}
+}
+
+func TestCancelClosesCaseOfReplacedSubscription(t *testing.T) {
+	orgID := uuid.New()
+	sub := pastDue("u1")
+	sub.OrgID = &orgID
+	store := NewMemoryStore()
+	m, subs, emails, now := newTestManager(t, store, sub)
+
+	subs.sub = &types.SubscriptionResponse{ID: "sub_2", UserID: "u1", OrgID: &orgID, Status: types.SubscriptionStatusPastDue}
+	*now = now.Add(7 * day)
+	if err := m.Tick(context.Background()); err != nil {
+		t.Fatalf("Tick() error = %v", err)
+	}
+	if subs.canceled != 0 || emails.cancelations != 0 || openCases(t, store) != 0 {
+		t.Errorf("canceled = %d, emails = %d, open cases = %d, want all 0", subs.canceled, emails.cancelations, openCases(t, store))
+	}
+}
+
+func TestCancelRetriesWithoutCancelingTwice(t *testing.T) {
+	ctx := context.Background()
+	store := &failingDeleteStore{MemoryStore: NewMemoryStore()}
+	m, subs, emails, now := newTestManager(t, store, pastDue("u1"))
+	*now = now.Add(7 * day)
+
+	// The email fails after the cancel; the case stays open for a retry
+	emails.cancelErr = errors.New("smtp down")
+	if err := m.Tick(ctx); err == nil {
+		t.Fatal("Tick() error = nil, want the email failure")
+	}
+	if subs.canceled != 1 || openCases(t, store) != 1 {
+		t.Fatalf("canceled = %d, open cases = %d, want 1, 1", subs.canceled, openCases(t, store))
+	}
+
+	// The email goes out but closing the case fails
+	emails.cancelErr = nil
+	store.err = errors.New("store down")
+	if err := m.Tick(ctx); err == nil {
+		t.Fatal("Tick() error = nil, want the delete failure")
+	}
+
+	store.err = nil
+	if err := m.Tick(ctx); err != nil {
+		t.Fatalf("Tick() error = %v", err)
+	}
+	if subs.canceled != 1 {
+		t.Errorf("canceled = %d, want 1", subs.canceled)
+	}
+	if emails.cancelations == 0 || openCases(t, store) != 0 {
+		t.Errorf("cancelation emails = %d, open cases = %d, want at least 1, 0", emails.cancelations, openCases(t, store))
+	}
+}
+
+func TestCancelFailureKeepsCase(t *testing.T) {
+	store := NewMemoryStore()
+	m, subs, emails, now := newTestManager(t, store, pastDue("u1"))
+	subs.cancelErr = types.ErrServiceUnavailable
+	*now = now.Add(7 * day)
+
+	if err := m.Tick(context.Background()); !errors.Is(err, types.ErrServiceUnavailable) {
+		t.Fatalf("Tick() error = %v, want service unavailable", err)
+	}
+	if emails.cancelations != 0 || openCases(t, store) != 1 {
+		t.Errorf("emails = %d, open cases = %d, want 0, 1", emails.cancelations, openCases(t, store))
+	}
+}
@@ -0,0 +1,98 @@
+package dunning
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrCaseNotFound is returned for subscriptions with no open dunning case
+var ErrCaseNotFound = errors.New("dunning: case not found")
+
+// Case tracks a past_due subscription through the dunning schedule
+type Case struct {
+	SubscriptionID string     `json:"subscription_id"`
+	UserID         string     `json:"user_id"`
+	OrgID          *uuid.UUID `json:"org_id,omitempty"`
+	Email          string     `json:"email"`
+	PastDueSince   time.Time  `json:"past_due_since"`
+	// RemindersSent is the number of schedule reminders already handled
+	RemindersSent int `json:"reminders_sent"`
+}
+
+// Store persists open dunning cases by subscription ID
+type Store interface {
+	// Get returns the case for the subscription, or ErrCaseNotFound
+	Get(ctx context.Context, subscriptionID string) (*Case, error)
+	// Put creates or replaces the case for its subscription
+	Put(ctx context.Context, c *Case) error
+	// Delete closes the case; deleting a missing case is not an error
+	Delete(ctx context.Context, subscriptionID string) error
+	// List returns every open case, oldest first
+	List(ctx context.Context) ([]*Case, error)
+}
+
+// MemoryStore is an in-memory Store for tests and single-instance services
+type MemoryStore struct {
+	mu    sync.Mutex
+	cases map[string]*Case
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{cases: map[string]*Case{}}
+}
+
+// Get returns a copy of the case for the subscription
+func (s *MemoryStore) Get(ctx context.Context, subscriptionID string) (*Case, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.cases[subscriptionID]
+	if !ok {
+		return nil, ErrCaseNotFound
+	}
+	clone := *c
+	return &clone, nil
+}
+
+// Put stores a copy of the case
+func (s *MemoryStore) Put(ctx context.Context, c *Case) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clone := *c
+	s.cases[clone.SubscriptionID] = &clone
+	return nil
+}
+
+// Delete closes the case for the subscription
+func (s *MemoryStore) Delete(ctx context.Context, subscriptionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.cases, subscriptionID)
+	return nil
+}
+
+// List returns copies of every open case, oldest first
+func (s *MemoryStore) List(ctx context.Context) ([]*Case, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cases := make([]*Case, 0, len(s.cases))
+	for _, c := range s.cases {
+		clone := *c
+		cases = append(cases, &clone)
+	}
+	sort.Slice(cases, func(i, j int) bool {
+		return cases[i].PastDueSince.Before(cases[j].PastDueSince)
+	})
+	return cases, nil
+}
@@ -0,0 +1,122 @@
+package entitlements
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// Feature names a capability a plan may include
+type Feature string
+
+// Well-known limit names
+const (
+	LimitPhoneNumbers = "phone_numbers"
+	LimitSMSPerMonth  = "sms_per_month"
+	LimitOrgSeats     = "org_seats"
+)
+
+// Unlimited is the limit value that places no bound on usage
+const Unlimited int64 = -1
+
+// Plan is the set of features and limits granted to a subscriber.
+// Limits missing from the map are zero.
+type Plan struct {
+	Features []Feature        `json:"features"`
+	Limits   map[string]int64 `json:"limits"`
+	// Trial overrides the plan while the subscription is trialing.
+	// Its features replace the plan's when set; its limits override per name.
+	Trial *Plan `json:"trial,omitempty"`
+}
+
+// Config maps subscription tiers to plans
+type Config struct {
+	Tiers map[types.SubscriptionTier]Plan `json:"tiers"`
+	// Inactive applies to users without an active or trialing subscription
+	Inactive Plan `json:"inactive"`
+}
+
+// Load decodes a JSON config and builds an engine from it
+func Load(r io.Reader) (*Engine, error) {
+	var config Config
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("entitlements: decode config: %w", err)
+	}
+	return New(config)
+}
+
+// LoadFile reads a JSON config from path and builds an engine from it
+func LoadFile(path string) (*Engine, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("entitlements: open config: %w", err)
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+// Validate checks that every tier is known and every limit is Unlimited or non-negative
+func (c Config) Validate() error {
+	for tier, plan := range c.Tiers {
+		switch tier {
+		case types.SubscriptionTierBasic, types.SubscriptionTierPro, types.SubscriptionTierEnterprise:
+		default:
+			return fmt.Errorf("entitlements: unknown tier %q", tier)
+		}
+		if err := plan.validate(string(tier)); err != nil {
+			return err
+		}
+	}
+	return c.Inactive.validate("inactive")
+}
+
+func (p Plan) validate(name string) error {
+	for limit, value := range p.Limits {
+		if value < Unlimited {
+			return fmt.Errorf("entitlements: %s limit %s must be %d or more, got %d", name, limit, Unlimited, value)
+		}
+	}
+	if p.Trial != nil {
+		if p.Trial.Trial != nil {
+			return fmt.Errorf("entitlements: %s trial cannot have its own trial", name)
+		}
+		return p.Trial.validate(name + " trial")
+	}
+	return nil
+}
+
+// clone returns a deep copy of the config
+func (c Config) clone() Config {
+	clone := Config{Inactive: c.Inactive.clone()}
+	if c.Tiers != nil {
+		clone.Tiers = make(map[types.SubscriptionTier]Plan, len(c.Tiers))
+		for tier, plan := range c.Tiers {
+			clone.Tiers[tier] = plan.clone()
+		}
+	}
+	return clone
+}
+
+// clone returns a deep copy of the plan, so callers cannot modify a shared config
+func (p Plan) clone() Plan {
+	clone := Plan{}
+	if p.Features != nil {
+		clone.Features = append([]Feature{}, p.Features...)
+	}
+	if p.Limits != nil {
+		clone.Limits = make(map[string]int64, len(p.Limits))
+		for name, value := range p.Limits {
+			clone.Limits[name] = value
+		}
+	}
+	if p.Trial != nil {
+		trial := p.Trial.clone()
+		clone.Trial = &trial
+	}
+	return clone
+}
@@ -0,0 +1,47 @@
+package entitlements
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadRejectsInvalidConfigs(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{"not JSON", `tiers: {}`},
+		{"unknown field", `{"tiers": {}, "plans": {}}`},
+		{"unknown tier", `{"tiers": {"platinum": {"features": []}}}`},
+		{"limit below unlimited", `{"tiers": {"basic": {"limits": {"phone_numbers": -2}}}}`},
+		{"negative inactive limit", `{"inactive": {"limits": {"org_seats": -5}}}`},
+		{"negative trial limit", `{"tiers": {"pro": {"trial": {"limits": {"sms_per_month": -3}}}}}`},
+		{"nested trial", `{"tiers": {"pro": {"trial": {"trial": {}}}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(strings.NewReader(tt.config)); err == nil {
+				t.Errorf("Load(%s) error = nil", tt.config)
+			}
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entitlements.json")
+	if err := os.WriteFile(path, []byte(testConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	engine, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if got := engine.Limit(nil, LimitPhoneNumbers); got != 0 {
+		t.Errorf("Limit(nil) = %d", got)
+	}
+	if _, err := LoadFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("LoadFile(missing) error = nil")
+	}
+}
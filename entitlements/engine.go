@@ -0,0 +1,106 @@
+package entitlements
+
+import (
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// GraceFunc reports whether a subscription that is no longer active keeps its entitlements
+type GraceFunc func(sub *types.SubscriptionResponse) bool
+
+// Engine answers feature and limit questions for a subscription
+type Engine struct {
+	config Config
+
+	// Grace, when set, keeps the tier's plan for past_due subscriptions it accepts
+	Grace GraceFunc
+}
+
+// New creates an engine from a validated config.
+// The engine keeps its own copy, so later changes to config do not affect it.
+func New(config Config) (*Engine, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &Engine{config: config.clone()}, nil
+}
+
+// Plan returns the effective plan for the subscription.
+// A nil or inactive subscription gets the inactive plan, unless it is past_due
+// and within its grace period. A trialing subscription gets its tier's plan
+// with the trial overrides applied. Unconfigured tiers get the inactive plan.
+// The returned plan is a copy that callers may modify.
+func (e *Engine) Plan(sub *types.SubscriptionResponse) Plan {
+	return e.plan(sub).clone()
+}
+
+// plan returns the effective plan without copying it, for read-only lookups
+func (e *Engine) plan(sub *types.SubscriptionResponse) Plan {
+	if sub == nil || !(sub.HasActiveSubscription() || e.inGrace(sub)) {
+		return e.config.Inactive
+	}
+	plan, ok := e.config.Tiers[sub.Tier]
+	if !ok {
+		return e.config.Inactive
+	}
+	if sub.Status == types.SubscriptionStatusTrialing && plan.Trial != nil {
+		return plan.withTrial()
+	}
+	return plan
+}
+
+// Can reports whether the subscription's effective plan includes the feature
+func (e *Engine) Can(sub *types.SubscriptionResponse, feature Feature) bool {
+	return e.plan(sub).Has(feature)
+}
+
+// Limit returns the named limit of the subscription's effective plan, which may be Unlimited
+func (e *Engine) Limit(sub *types.SubscriptionResponse, name string) int64 {
+	return e.plan(sub).Limit(name)
+}
+
+// Allows reports whether used units leave room for one more under the named limit
+func (e *Engine) Allows(sub *types.SubscriptionResponse, name string, used int64) bool {
+	return e.plan(sub).Allows(name, used)
+}
+
+// Has reports whether the plan includes the feature
+func (p Plan) Has(feature Feature) bool {
+	for _, f := range p.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// Limit returns the named limit, zero when it is not configured
+func (p Plan) Limit(name string) int64 {
+	return p.Limits[name]
+}
+
+// Allows reports whether used units leave room for one more under the named limit
+func (p Plan) Allows(name string, used int64) bool {
+	limit := p.Limit(name)
+	return limit == Unlimited || used < limit
+}
+
+func (e *Engine) inGrace(sub *types.SubscriptionResponse) bool {
+	return sub.Status == types.SubscriptionStatusPastDue && e.Grace != nil && e.Grace(sub)
+}
+
+func (p Plan) withTrial() Plan {
+	merged := Plan{
+		Features: p.Features,
+		Limits:   make(map[string]int64, len(p.Limits)+len(p.Trial.Limits)),
+	}
+	if p.Trial.Features != nil {
+		merged.Features = p.Trial.Features
+	}
+	for name, value := range p.Limits {
+		merged.Limits[name] = value
+	}
+	for name, value := range p.Trial.Limits {
+		merged.Limits[name] = value
+	}
+	return merged
+}
@@ -0,0 +1,172 @@
+package entitlements
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+const testConfig = `{
+  "inactive": {"features": [], "limits": {}},
+  "tiers": {
+    "basic": {"features": ["sms"], "limits": {"phone_numbers": 1, "sms_per_month": 500, "org_seats": 1}},
+    "pro": {
+      "features": ["sms", "voice", "analytics"],
+      "limits": {"phone_numbers": 5, "sms_per_month": 5000, "org_seats": 10},
+      "trial": {"limits": {"phone_numbers": 1, "sms_per_month": 100}}
+    },
+    "enterprise": {"features": ["sms", "voice", "analytics", "sso"], "limits": {"phone_numbers": -1, "sms_per_month": -1, "org_seats": -1}}
+  }
+}`
+
+func newTestEngine(t *testing.T) *Engine {
+	t.Helper()
+	engine, err := Load(strings.NewReader(testConfig))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	return engine
+}
+
+func sub(tier types.SubscriptionTier, status types.SubscriptionStatus) *types.SubscriptionResponse {
+	return &types.SubscriptionResponse{ID: "sub_1", Tier: tier, Status: status}
+}
+
+func TestEngineFeatures(t *testing.T) {
+	engine := newTestEngine(t)
+
+	tests := []struct {
+		name    string
+		sub     *types.SubscriptionResponse
+		feature Feature
+		want    bool
+	}{
+		{"basic has sms", sub(types.SubscriptionTierBasic, types.SubscriptionStatusActive), "sms", true},
+		{"basic lacks voice", sub(types.SubscriptionTierBasic, types.SubscriptionStatusActive), "voice", false},
+		{"pro has analytics", sub(types.SubscriptionTierPro, types.SubscriptionStatusActive), "analytics", true},
+		{"pro lacks sso", sub(types.SubscriptionTierPro, types.SubscriptionStatusActive), "sso", false},
+		{"enterprise has sso", sub(types.SubscriptionTierEnterprise, types.SubscriptionStatusActive), "sso", true},
+		{"pro trial keeps pro features", sub(types.SubscriptionTierPro, types.SubscriptionStatusTrialing), "voice", true},
+		{"unknown feature", sub(types.SubscriptionTierEnterprise, types.SubscriptionStatusActive), "fax", false},
+		{"nil subscription", nil, "sms", false},
+		{"canceled", sub(types.SubscriptionTierEnterprise, types.SubscriptionStatusCanceled), "sms", false},
+		{"past_due without grace", sub(types.SubscriptionTierPro, types.SubscriptionStatusPastDue), "sms", false},
+		{"unknown plan", sub("platinum", types.SubscriptionStatusActive), "sms", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := engine.Can(tt.sub, tt.feature); got != tt.want {
+				t.Errorf("Can(%s) = %v, want %v", tt.feature, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngineLimits(t *testing.T) {
+	engine := newTestEngine(t)
+	pro := sub(types.SubscriptionTierPro, types.SubscriptionStatusActive)
+	trial := sub(types.SubscriptionTierPro, types.SubscriptionStatusTrialing)
+	enterprise := sub(types.SubscriptionTierEnterprise, types.SubscriptionStatusActive)
+
+	tests := []struct {
+		name      string
+		sub       *types.SubscriptionResponse
+		limit     string
+		used      int64
+		wantLimit int64
+		wantAllow bool
+	}{
+		{"below the limit", pro, LimitPhoneNumbers, 4, 5, true},
+		{"at the limit", pro, LimitPhoneNumbers, 5, 5, false},
+		{"trial override", trial, LimitPhoneNumbers, 1, 1, false},
+		{"trial keeps limits it does not override", trial, LimitOrgSeats, 9, 10, true},
+		{"unlimited", enterprise, LimitSMSPerMonth, 1 << 40, Unlimited, true},
+		{"unconfigured limit is zero", pro, "fax_pages", 0, 0, false},
+		{"inactive plan", sub(types.SubscriptionTierPro, types.SubscriptionStatusUnpaid), LimitPhoneNumbers, 0, 0, false},
+		{"unknown plan", sub("platinum", types.SubscriptionStatusActive), LimitPhoneNumbers, 0, 0, false},
+		{"nil subscription", nil, LimitOrgSeats, 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := engine.Limit(tt.sub, tt.limit); got != tt.wantLimit {
+				t.Errorf("Limit(%s) = %d, want %d", tt.limit, got, tt.wantLimit)
+			}
+			if got := engine.Allows(tt.sub, tt.limit, tt.used); got != tt.wantAllow {
+				t.Errorf("Allows(%s, %d) = %v, want %v", tt.limit, tt.used, got, tt.wantAllow)
+			}
+		})
+	}
+}
+
+func TestEngineGrace(t *testing.T) {
+	engine := newTestEngine(t)
+	engine.Grace = func(s *types.SubscriptionResponse) bool { return s.ID == "sub_grace" }
+
+	inGrace := sub(types.SubscriptionTierPro, types.SubscriptionStatusPastDue)
+	inGrace.ID = "sub_grace"
+	if !engine.Can(inGrace, "voice") || engine.Limit(inGrace, LimitPhoneNumbers) != 5 {
+		t.Errorf("Plan(past_due in grace) = %+v, want the pro plan", engine.Plan(inGrace))
+	}
+	if engine.Can(sub(types.SubscriptionTierPro, types.SubscriptionStatusPastDue), "voice") {
+		t.Error("Can() = true for past_due outside grace")
+	}
+
+	// Grace only covers past_due
+	unpaid := sub(types.SubscriptionTierPro, types.SubscriptionStatusUnpaid)
+	unpaid.ID = "sub_grace"
+	if engine.Can(unpaid, "voice") {
+		t.Error("Can() = true for unpaid in grace")
+	}
+}
+
+func TestEnginePlanReturnsCopies(t *testing.T) {
+	engine := newTestEngine(t)
+
+	for _, s := range []*types.SubscriptionResponse{
+		sub(types.SubscriptionTierPro, types.SubscriptionStatusActive),
+		sub(types.SubscriptionTierPro, types.SubscriptionStatusTrialing),
+		nil,
+	} {
+		plan := engine.Plan(s)
+		if plan.Limits == nil {
+			t.Fatalf("Plan() has no limits map")
+		}
+		plan.Limits[LimitPhoneNumbers] = Unlimited
+		plan.Features = append(plan.Features[:0], "sso")
+		if plan.Trial != nil {
+			plan.Trial.Limits[LimitPhoneNumbers] = Unlimited
+		}
+	}
+
+	pro := sub(types.SubscriptionTierPro, types.SubscriptionStatusActive)
+	if got := engine.Limit(pro, LimitPhoneNumbers); got != 5 {
+		t.Errorf("Limit() after modifying a returned plan = %d, want 5", got)
+	}
+	if engine.Can(pro, "sso") || !engine.Can(pro, "sms") {
+		t.Errorf("features after modifying a returned plan = %v", engine.Plan(pro).Features)
+	}
+	if got := engine.Limit(sub(types.SubscriptionTierPro, types.SubscriptionStatusTrialing), LimitPhoneNumbers); got != 1 {
+		t.Errorf("trial Limit() after modifying a returned plan = %d, want 1", got)
+	}
+	if got := engine.Limit(nil, LimitPhoneNumbers); got != 0 {
+		t.Errorf("inactive Limit() after modifying a returned plan = %d, want 0", got)
+	}
+}
+
+func TestNewCopiesConfig(t *testing.T) {
+	config := Config{Tiers: map[types.SubscriptionTier]Plan{
+		types.SubscriptionTierBasic: {Features: []Feature{"sms"}, Limits: map[string]int64{LimitPhoneNumbers: 1}},
+	}}
+	engine, err := New(config)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	config.Tiers[types.SubscriptionTierBasic].Limits[LimitPhoneNumbers] = 100
+	config.Tiers[types.SubscriptionTierBasic].Features[0] = "voice"
+
+	basic := sub(types.SubscriptionTierBasic, types.SubscriptionStatusActive)
+	if engine.Limit(basic, LimitPhoneNumbers) != 1 || !engine.Can(basic, "sms") {
+		t.Errorf("Plan() = %+v after the caller changed its config", engine.Plan(basic))
+	}
+}
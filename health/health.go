@@ -0,0 +1,198 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// DefaultTimeout bounds checks registered without a timeout
+const DefaultTimeout = 5 * time.Second
+
+// ErrDegraded marks a probe failure as degraded rather than down.
+// Wrap it in a probe error to report a dependency that still works partially.
+var ErrDegraded = errors.New("health: degraded")
+
+// Probe checks a single dependency, returning nil when it is healthy
+type Probe func(ctx context.Context) error
+
+// Check is a named probe registered with a Registry
+type Check struct {
+	Name    string
+	Probe   Probe
+	Timeout time.Duration
+	// Critical checks take the whole service down when they fail;
+	// non-critical failures only degrade it
+	Critical bool
+}
+
+// Registry runs registered checks and rolls them up into a types.HealthResponse
+type Registry struct {
+	version string
+
+	mu     sync.RWMutex
+	checks []Check
+
+	// Now returns the current time; defaults to time.Now
+	Now func() time.Time
+}
+
+// NewRegistry creates an empty registry reporting the given service version
+func NewRegistry(version string) *Registry {
+	return &Registry{version: version}
+}
+
+// Register adds a check; names must be unique
+func (r *Registry) Register(check Check) error {
+	if check.Name == "" || check.Probe == nil {
+		return errors.New("health: check needs a name and a probe")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.checks {
+		if existing.Name == check.Name {
+			return fmt.Errorf("health: check %q already registered", check.Name)
+		}
+	}
+	r.checks = append(r.checks, check)
+	return nil
+}
+
+// Run executes every check concurrently and returns the aggregated report.
+// The overall status is down if any critical check fails, degraded if any
+// other check fails or reports ErrDegraded, and healthy otherwise.
+func (r *Registry) Run(ctx context.Context) types.HealthResponse {
+	r.mu.RLock()
+	checks := append([]Check(nil), r.checks...)
+	r.mu.RUnlock()
+
+	results := make([]types.ServiceHealth, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = runCheck(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	resp := types.HealthResponse{
+		Status:    types.StatusHealthy,
+		Version:   r.version,
+		Timestamp: r.now(),
+		Services:  make(map[string]types.ServiceHealth, len(checks)),
+	}
+	for i, check := range checks {
+		result := results[i]
+		resp.Services[check.Name] = result
+
+		switch {
+		case result.Status == types.StatusDown && check.Critical:
+			resp.Status = types.StatusDown
+		case result.Status != types.StatusHealthy && resp.Status == types.StatusHealthy:
+			resp.Status = types.StatusDegraded
+		}
+	}
+	return resp
+}
+
+// Names returns the registered check names in sorted order
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, len(r.checks))
+	for i, check := range r.checks {
+		names[i] = check.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+// runCheck runs one probe under its timeout, even if the probe ignores its context
+func runCheck(ctx context.Context, check Check) types.ServiceHealth {
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- fmt.Errorf("probe panicked: %v", p)
+			}
+		}()
+		done <- check.Probe(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+
+	result := types.ServiceHealth{
+		Status:  types.StatusHealthy,
+		Latency: time.Since(start).Round(time.Microsecond).String(),
+	}
+	if err != nil {
+		result.Status = types.StatusDown
+		if errors.Is(err, ErrDegraded) {
+			result.Status = types.StatusDegraded
+		}
+		result.Message = err.Error()
+	}
+	return result
+}
+
+// LivenessHandler reports that the process is up without running any checks
+func (r *Registry) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		writeHealth(w, http.StatusOK, types.HealthResponse{
+			Status:    types.StatusHealthy,
+			Version:   r.version,
+			Timestamp: r.now(),
+		})
+	})
+}
+
+// ReadinessHandler runs every check and responds 503 when the service is down
+func (r *Registry) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		resp := r.Run(req.Context())
+		status := http.StatusOK
+		if resp.Status == types.StatusDown {
+			status = http.StatusServiceUnavailable
+		}
+		writeHealth(w, status, resp)
+	})
+}
+
+func writeHealth(w http.ResponseWriter, status int, resp types.HealthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (r *Registry) now() time.Time {
+	if r.Now != nil {
+		return r.Now()
+	}
+	return time.Now()
+}
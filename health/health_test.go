@@ -0,0 +1,221 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+var testNow = time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+var (
+	healthy  Probe = func(ctx context.Context) error { return nil }
+	failing  Probe = func(ctx context.Context) error { return errors.New("connection refused") }
+	degraded Probe = func(ctx context.Context) error { return fmt.Errorf("%w: replica lag", ErrDegraded) }
+)
+
+func newTestRegistry(t *testing.T, checks ...Check) *Registry {
+	t.Helper()
+	r := NewRegistry("1.2.3")
+	r.Now = func() time.Time { return testNow }
+	for _, check := range checks {
+		if err := r.Register(check); err != nil {
+			t.Fatalf("Register(%s) error = %v", check.Name, err)
+		}
+	}
+	return r
+}
+
+func TestRunAggregatesStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []Check
+		want   string
+	}{
+		{"no checks", nil, types.StatusHealthy},
+		{"all healthy", []Check{{Name: "db", Probe: healthy, Critical: true}, {Name: "cache", Probe: healthy}}, types.StatusHealthy},
+		{"optional check down", []Check{{Name: "db", Probe: healthy, Critical: true}, {Name: "cache", Probe: failing}}, types.StatusDegraded},
+		{"critical check degraded", []Check{{Name: "db", Probe: degraded, Critical: true}}, types.StatusDegraded},
+		{"critical check down", []Check{{Name: "db", Probe: failing, Critical: true}, {Name: "cache", Probe: healthy}}, types.StatusDown},
+		{"down wins over degraded", []Check{{Name: "cache", Probe: degraded}, {Name: "db", Probe: failing, Critical: true}}, types.StatusDown},
+		{"panicking critical probe", []Check{{Name: "db", Probe: func(ctx context.Context) error { panic("nil pool") }, Critical: true}}, types.StatusDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := newTestRegistry(t, tt.checks...).Run(context.Background())
+			if resp.Status != tt.want {
+				t.Errorf("Status = %s, want %s", resp.Status, tt.want)
+			}
+			if resp.Version != "1.2.3" || !resp.Timestamp.Equal(testNow) || len(resp.Services) != len(tt.checks) {
+				t.Errorf("report = %+v", resp)
+			}
+		})
+	}
+}
+
+func TestRunReportsEachCheck(t *testing.T) {
+	r := newTestRegistry(t,
+		Check{Name: "db", Probe: healthy, Critical: true},
+		Check{Name: "cache", Probe: degraded},
+		Check{Name: "search", Probe: failing},
+	)
+	resp := r.Run(context.Background())
+
+	want := map[string]types.ServiceHealth{
+		"db":     {Status: types.StatusHealthy},
+		"cache":  {Status: types.StatusDegraded, Message: "health: degraded: replica lag"},
+		"search": {Status: types.StatusDown, Message: "connection refused"},
+	}
+	for name, w := range want {
+		got := resp.Services[name]
+		if got.Status != w.Status || got.Message != w.Message || got.Latency == "" {
+			t.Errorf("Services[%s] = %+v, want %+v with a latency", name, got, w)
+		}
+	}
+	if got := r.Names(); !reflect.DeepEqual(got, []string{"cache", "db", "search"}) {
+		t.Errorf("Names() = %v", got)
+	}
+}
+
+func TestRunTimesOutProbes(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	r := newTestRegistry(t,
+		// Honors its context
+		Check{Name: "slow", Timeout: 20 * time.Millisecond, Critical: true, Probe: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+		// Ignores its context entirely
+		Check{Name: "stuck", Timeout: 20 * time.Millisecond, Probe: func(ctx context.Context) error {
+			<-release
+			return nil
+		}},
+		Check{Name: "fast", Timeout: time.Second, Probe: healthy},
+	)
+
+	start := time.Now()
+	resp := r.Run(context.Background())
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Run() took %s, want it bounded by the probe timeouts", elapsed)
+	}
+	if resp.Status != types.StatusDown {
+		t.Errorf("Status = %s, want down", resp.Status)
+	}
+	for _, name := range []string{"slow", "stuck"} {
+		if got := resp.Services[name]; got.Status != types.StatusDown || got.Message != "timed out after 20ms" {
+			t.Errorf("Services[%s] = %+v, want a timeout", name, got)
+		}
+	}
+	if got := resp.Services["fast"]; got.Status != types.StatusHealthy {
+		t.Errorf("Services[fast] = %+v", got)
+	}
+}
+
+func TestRunHonorsCallerContext(t *testing.T) {
+	r := newTestRegistry(t, Check{Name: "db", Timeout: time.Hour, Critical: true, Probe: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if resp := r.Run(ctx); resp.Status != types.StatusDown {
+		t.Errorf("Status = %s with a canceled context, want down", resp.Status)
+	}
+}
+
+func TestRegisterRejectsInvalidChecks(t *testing.T) {
+	r := newTestRegistry(t, Check{Name: "db", Probe: healthy})
+	for name, check := range map[string]Check{
+		"missing name":  {Probe: healthy},
+		"missing probe": {Name: "cache"},
+		"duplicate":     {Name: "db", Probe: healthy},
+	} {
+		if err := r.Register(check); err == nil {
+			t.Errorf("Register(%s) error = nil", name)
+		}
+	}
+}
+
+func TestHandlers(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    func(r *Registry) http.Handler
+		checks     []Check
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			"liveness ignores failing checks",
+			(*Registry).LivenessHandler,
+			[]Check{{Name: "db", Probe: failing, Critical: true}},
+			http.StatusOK,
+			`{"status":"healthy","version":"1.2.3","timestamp":"2026-05-01T12:00:00Z"}`,
+		},
+		{
+			"readiness healthy",
+			(*Registry).ReadinessHandler,
+			[]Check{{Name: "db", Probe: healthy, Critical: true}},
+			http.StatusOK,
+			`{"status":"healthy","version":"1.2.3","timestamp":"2026-05-01T12:00:00Z","services":{"db":{"status":"healthy"}}}`,
+		},
+		{
+			"readiness degraded stays ready",
+			(*Registry).ReadinessHandler,
+			[]Check{{Name: "db", Probe: healthy, Critical: true}, {Name: "cache", Probe: failing}},
+			http.StatusOK,
+			`{"status":"degraded","version":"1.2.3","timestamp":"2026-05-01T12:00:00Z","services":{"cache":{"status":"down","message":"connection refused"},"db":{"status":"healthy"}}}`,
+		},
+		{
+			"readiness down",
+			(*Registry).ReadinessHandler,
+			[]Check{{Name: "db", Probe: failing, Critical: true}, {Name: "cache", Probe: degraded}},
+			http.StatusServiceUnavailable,
+			`{"status":"down","version":"1.2.3","timestamp":"2026-05-01T12:00:00Z","services":{"cache":{"status":"degraded","message":"health: degraded: replica lag"},"db":{"status":"down","message":"connection refused"}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tt.handler(newTestRegistry(t, tt.checks...)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if ct, cc := rec.Header().Get("Content-Type"), rec.Header().Get("Cache-Control"); ct != "application/json" || cc != "no-store" {
+				t.Errorf("headers = %q, %q", ct, cc)
+			}
+			if got := stripLatency(t, rec.Body.Bytes()); got != tt.wantBody {
+				t.Errorf("body =\n%s\nwant\n%s", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+// stripLatency re-encodes a health body without the nondeterministic latencies
+func stripLatency(t *testing.T, body []byte) string {
+	t.Helper()
+	var resp types.HealthResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		t.Fatalf("body %s is not a HealthResponse: %v", body, err)
+	}
+	for name, service := range resp.Services {
+		if service.Latency == "" {
+			t.Errorf("Services[%s] has no latency", name)
+		}
+		service.Latency = ""
+		resp.Services[name] = service
+	}
+	data, _ := json.Marshal(resp)
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,44 @@
+package health
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// Pinger is implemented by the service contracts that expose Health,
+// such as contracts.UserServiceClient and contracts.SubscriptionServiceClient
+type Pinger interface {
+	Health(ctx context.Context) error
+}
+
+// Reporter is implemented by the service contracts that expose GetServiceHealth,
+// such as contracts.PhoneServiceClient
+type Reporter interface {
+	GetServiceHealth(ctx context.Context) (*types.HealthResponse, error)
+}
+
+// PingProbe probes a downstream service through its contract Health method
+func PingProbe(service Pinger) Probe {
+	return service.Health
+}
+
+// ReportProbe probes a downstream service through its contract GetServiceHealth method.
+// A degraded report is wrapped in ErrDegraded; a down report fails the probe.
+func ReportProbe(service Reporter) Probe {
+	return func(ctx context.Context) error {
+		report, err := service.GetServiceHealth(ctx)
+		if err != nil {
+			return err
+		}
+		switch report.Status {
+		case types.StatusHealthy, types.StatusOK:
+			return nil
+		case types.StatusDegraded:
+			return fmt.Errorf("%w: downstream reported %s", ErrDegraded, report.Status)
+		default:
+			return fmt.Errorf("downstream reported %s", report.Status)
+		}
+	}
+}
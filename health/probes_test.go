@@ -0,0 +1,58 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+type pinger struct{ err error }
+
+func (p pinger) Health(ctx context.Context) error { return p.err }
+
+type reporter struct {
+	status string
+	err    error
+}
+
+func (r reporter) GetServiceHealth(ctx context.Context) (*types.HealthResponse, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &types.HealthResponse{Status: r.status}, nil
+}
+
+func TestPingProbe(t *testing.T) {
+	if err := PingProbe(pinger{})(context.Background()); err != nil {
+		t.Errorf("PingProbe(healthy) error = %v", err)
+	}
+	unavailable := errors.New("503 from user service")
+	if err := PingProbe(pinger{err: unavailable})(context.Background()); !errors.Is(err, unavailable) {
+		t.Errorf("PingProbe(failing) error = %v", err)
+	}
+}
+
+func TestReportProbe(t *testing.T) {
+	tests := []struct {
+		name       string
+		service    reporter
+		wantStatus string
+	}{
+		{"healthy", reporter{status: types.StatusHealthy}, types.StatusHealthy},
+		{"ok", reporter{status: types.StatusOK}, types.StatusHealthy},
+		{"degraded", reporter{status: types.StatusDegraded}, types.StatusDegraded},
+		{"down", reporter{status: types.StatusDown}, types.StatusDown},
+		{"unknown status", reporter{status: "starting"}, types.StatusDown},
+		{"unreachable", reporter{err: errors.New("dial tcp: i/o timeout")}, types.StatusDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runCheck(context.Background(), Check{Name: "phone", Probe: ReportProbe(tt.service)})
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s (%s), want %s", result.Status, result.Message, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// client holds the transport shared by every service client in this package
+type client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func newClient(baseURL string, httpClient *http.Client) client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: httpClient,
+	}
+}
+
+// do sends a request for the given route pattern and decodes the envelope data into out.
+// Path parameters replace the {name} segments of the pattern in order.
+func (c client) do(ctx context.Context, route string, params []string, query url.Values, body, out interface{}) error {
+	method, path, err := expandRoute(route, params)
+	if err != nil {
+		return err
+	}
+
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("httpclient: encode request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return fmt.Errorf("httpclient: build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("httpclient: %s %s: %w", method, path, err)
+	}
+
+	data, _, err := types.DecodeResponse[json.RawMessage](resp)
+	if err != nil {
+		return err
+	}
+
+	if out == nil || len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("httpclient: decode data: %w", err)
+	}
+	return nil
+}
+
+// expandRoute splits a "METHOD /path" pattern and substitutes its path parameters
+func expandRoute(route string, params []string) (string, string, error) {
+	method, pattern, ok := strings.Cut(route, " ")
+	if !ok {
+		return "", "", fmt.Errorf("httpclient: invalid route %q", route)
+	}
+
+	segments := strings.Split(pattern, "/")
+	next := 0
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+		if next >= len(params) {
+			return "", "", fmt.Errorf("httpclient: missing path parameter %s for route %q", segment, route)
+		}
+		segments[i] = url.PathEscape(params[next])
+		next++
+	}
+	if next != len(params) {
+		return "", "", fmt.Errorf("httpclient: too many path parameters for route %q", route)
+	}
+
+	return method, strings.Join(segments, "/"), nil
+}
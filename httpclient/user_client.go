@@ -0,0 +1,201 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/jonnyt98/atlas-shared/contracts"
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// UserClient implements contracts.UserServiceClient over HTTP
+type UserClient struct {
+	client
+}
+
+var _ contracts.UserServiceClient = (*UserClient)(nil)
+
+// NewUserClient creates a user service client for the given base URL.
+// A nil httpClient falls back to http.DefaultClient.
+func NewUserClient(baseURL string, httpClient *http.Client) *UserClient {
+	return &UserClient{client: newClient(baseURL, httpClient)}
+}
+
+// CreateUser creates a new user
+func (c *UserClient) CreateUser(ctx context.Context, req types.UserCreateRequest) (*types.UserResponse, error) {
+	var user types.UserResponse
+	if err := c.do(ctx, contracts.UserRouteCreate, nil, nil, req, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetUserByID fetches a user by ID
+func (c *UserClient) GetUserByID(ctx context.Context, id string) (*types.UserResponse, error) {
+	var user types.UserResponse
+	if err := c.do(ctx, contracts.UserRouteGetByID, []string{id}, nil, nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetUserByEmail fetches a user by email address
+func (c *UserClient) GetUserByEmail(ctx context.Context, email string) (*types.UserResponse, error) {
+	var user types.UserResponse
+	query := url.Values{"email": {email}}
+	if err := c.do(ctx, contracts.UserRouteGetByEmail, nil, query, nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetUserByGoogleID fetches a user by linked Google account ID
+func (c *UserClient) GetUserByGoogleID(ctx context.Context, googleID string) (*types.UserResponse, error) {
+	var user types.UserResponse
+	query := url.Values{"google_id": {googleID}}
+	if err := c.do(ctx, contracts.UserRouteGetByGoogleID, nil, query, nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateUser applies a partial update to a user
+func (c *UserClient) UpdateUser(ctx context.Context, id string, req types.UserUpdateRequest) (*types.UserResponse, error) {
+	var user types.UserResponse
+	if err := c.do(ctx, contracts.UserRouteUpdate, []string{id}, nil, req, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// DeleteUser deletes a user
+func (c *UserClient) DeleteUser(ctx context.Context, id string) error {
+	return c.do(ctx, contracts.UserRouteDelete, []string{id}, nil, nil, nil)
+}
+
+// ListUsers lists users matching the query
+func (c *UserClient) ListUsers(ctx context.Context, query types.UserListQuery) (*types.UserListResponse, error) {
+	var list types.UserListResponse
+	if err := c.do(ctx, contracts.UserRouteList, nil, userListValues(query), nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// UpdateEmailVerification updates a user's email verification state
+func (c *UserClient) UpdateEmailVerification(ctx context.Context, id string, req types.EmailVerificationRequest) (*types.UserResponse, error) {
+	var user types.UserResponse
+	if err := c.do(ctx, contracts.UserRouteUpdateEmailVerification, []string{id}, nil, req, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// VerifyEmail verifies an email address using a verification token
+func (c *UserClient) VerifyEmail(ctx context.Context, req types.VerifyEmailRequest) (*types.VerifyEmailResponse, error) {
+	var resp types.VerifyEmailResponse
+	if err := c.do(ctx, contracts.UserRouteVerifyEmail, nil, nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ResendVerification sends a new verification email to a user
+func (c *UserClient) ResendVerification(ctx context.Context, id string) error {
+	return c.do(ctx, contracts.UserRouteResendVerification, []string{id}, nil, nil, nil)
+}
+
+// AuthenticateUser checks a user's credentials
+func (c *UserClient) AuthenticateUser(ctx context.Context, req types.AuthenticateRequest) (*types.AuthenticateResponse, error) {
+	var resp types.AuthenticateResponse
+	if err := c.do(ctx, contracts.UserRouteAuthenticate, nil, nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// LinkGoogleAccount links a Google account to a user
+func (c *UserClient) LinkGoogleAccount(ctx context.Context, id string, req types.GoogleLinkRequest) (*types.UserResponse, error) {
+	var user types.UserResponse
+	if err := c.do(ctx, contracts.UserRouteLinkGoogleAccount, []string{id}, nil, req, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdatePassword replaces a user's password hash
+func (c *UserClient) UpdatePassword(ctx context.Context, id string, req types.PasswordUpdateRequest) (*types.UserResponse, error) {
+	var user types.UserResponse
+	if err := c.do(ctx, contracts.UserRouteUpdatePassword, []string{id}, nil, req, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateSubscription updates a user's subscription
+func (c *UserClient) UpdateSubscription(ctx context.Context, id string, req types.SubscriptionUpdateRequest) (*types.UserResponse, error) {
+	var user types.UserResponse
+	if err := c.do(ctx, contracts.UserRouteUpdateSubscription, []string{id}, nil, req, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetSubscription fetches a user's subscription
+func (c *UserClient) GetSubscription(ctx context.Context, id string) (*types.UserSubscriptionResponse, error) {
+	var resp types.UserSubscriptionResponse
+	if err := c.do(ctx, contracts.UserRouteGetSubscription, []string{id}, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CancelSubscription cancels a user's subscription
+func (c *UserClient) CancelSubscription(ctx context.Context, id string) (*types.UserResponse, error) {
+	var user types.UserResponse
+	if err := c.do(ctx, contracts.UserRouteCancelSubscription, []string{id}, nil, nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UserExists reports whether a user with the given email exists
+func (c *UserClient) UserExists(ctx context.Context, email string) (bool, error) {
+	var resp types.UserExistsResponse
+	query := url.Values{"email": {email}}
+	if err := c.do(ctx, contracts.UserRouteExists, nil, query, nil, &resp); err != nil {
+		return false, err
+	}
+	return resp.Exists, nil
+}
+
+// Health checks the user service health
+func (c *UserClient) Health(ctx context.Context) error {
+	return c.do(ctx, contracts.UserRouteHealth, nil, nil, nil, nil)
+}
+
+// userListValues encodes a UserListQuery as URL query parameters
+func userListValues(query types.UserListQuery) url.Values {
+	values := url.Values{}
+	if query.Page > 0 {
+		values.Set("page", strconv.Itoa(query.Page))
+	}
+	if query.Limit > 0 {
+		values.Set("limit", strconv.Itoa(query.Limit))
+	}
+	if query.Cursor != "" {
+		values.Set("cursor", query.Cursor)
+	}
+	if query.Role != "" {
+		values.Set("role", query.Role)
+	}
+	if query.OrgID != nil {
+		values.Set("org_id", query.OrgID.String())
+	}
+	if query.EmailVerified != nil {
+		values.Set("email_verified", strconv.FormatBool(*query.EmailVerified))
+	}
+	return values
+}
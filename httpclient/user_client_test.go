@@ -0,0 +1,227 @@
+package httpclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jonnyt98/atlas-shared/contracts"
+	"github.com/jonnyt98/atlas-shared/httpclient"
+	"github.com/jonnyt98/atlas-shared/httpserver"
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+func TestListUsersEncodesQuery(t *testing.T) {
+	orgID := uuid.MustParse("6f1c2d3e-4b5a-4c6d-8e7f-0123456789ab")
+	verified := false
+
+	tests := []struct {
+		name  string
+		query types.UserListQuery
+		want  url.Values
+	}{
+		{"empty", types.UserListQuery{}, url.Values{}},
+		{
+			"all fields",
+			types.UserListQuery{Page: 2, Limit: 50, Cursor: "abc", Role: "admin", OrgID: &orgID, EmailVerified: &verified},
+			url.Values{
+				"page":           {"2"},
+				"limit":          {"50"},
+				"cursor":         {"abc"},
+				"role":           {"admin"},
+				"org_id":         {orgID.String()},
+				"email_verified": {"false"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got url.Values
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet || r.URL.Path != "/users" {
+					t.Errorf("request = %s %s, want GET /users", r.Method, r.URL.Path)
+				}
+				got = r.URL.Query()
+				writeEnvelope(t, w, http.StatusOK, types.NewResponse(types.UserListResponse{}))
+			}))
+			defer srv.Close()
+
+			client := httpclient.NewUserClient(srv.URL, srv.Client())
+			if _, err := client.ListUsers(context.Background(), tt.query); err != nil {
+				t.Fatalf("ListUsers() error = %v", err)
+			}
+			if got.Encode() != tt.want.Encode() {
+				t.Errorf("query = %q, want %q", got.Encode(), tt.want.Encode())
+			}
+		})
+	}
+}
+
+func TestGetUserByIDDecodesEnvelope(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/users/user%2F1" {
+			t.Errorf("path = %q, want the escaped id", r.URL.EscapedPath())
+		}
+		writeEnvelope(t, w, http.StatusOK, types.NewResponse(types.UserResponse{ID: "user/1", Email: "a@example.com", Role: "user"}))
+	}))
+	defer srv.Close()
+
+	client := httpclient.NewUserClient(srv.URL+"/", srv.Client())
+	user, err := client.GetUserByID(context.Background(), "user/1")
+	if err != nil {
+		t.Fatalf("GetUserByID() error = %v", err)
+	}
+	if user.ID != "user/1" || user.Email != "a@example.com" || user.Role != "user" {
+		t.Errorf("user = %+v", user)
+	}
+}
+
+func TestErrorEnvelopeBecomesAPIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiErr := types.ErrNotFound.WithDetails("user 42")
+		writeEnvelope(t, w, apiErr.HTTPStatus(), apiErr.Response())
+	}))
+	defer srv.Close()
+
+	client := httpclient.NewUserClient(srv.URL, srv.Client())
+	_, err := client.GetUserByID(context.Background(), "42")
+
+	var apiErr *types.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error = %T %v, want *types.APIError", err, err)
+	}
+	if apiErr.Code != types.ErrorCodeNotFound || apiErr.Details != "user 42" {
+		t.Errorf("error = %+v", apiErr)
+	}
+	if !errors.Is(err, types.ErrNotFound) {
+		t.Error("errors.Is(err, types.ErrNotFound) = false")
+	}
+}
+
+func TestErrorStatusWithoutEnvelope(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "upstream down", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	client := httpclient.NewUserClient(srv.URL, srv.Client())
+	err := client.Health(context.Background())
+	if !errors.Is(err, &types.APIError{Code: types.ErrorCodeServiceUnavailable}) {
+		t.Errorf("Health() error = %v, want service_unavailable", err)
+	}
+}
+
+// fakeUsers implements the user service methods exercised by the round trip test
+type fakeUsers struct {
+	contracts.UserServiceClient
+
+	users     map[string]types.UserResponse
+	lastQuery types.UserListQuery
+}
+
+func (f *fakeUsers) CreateUser(ctx context.Context, req types.UserCreateRequest) (*types.UserResponse, error) {
+	user := types.UserResponse{ID: "u1", Email: req.Email, Role: req.Role, OrgID: req.OrgID}
+	f.users[user.ID] = user
+	return &user, nil
+}
+
+func (f *fakeUsers) GetUserByID(ctx context.Context, id string) (*types.UserResponse, error) {
+	user, ok := f.users[id]
+	if !ok {
+		return nil, types.ErrNotFound.WithDetails(id)
+	}
+	return &user, nil
+}
+
+func (f *fakeUsers) ListUsers(ctx context.Context, query types.UserListQuery) (*types.UserListResponse, error) {
+	f.lastQuery = query
+	list := &types.UserListResponse{Page: query.Page, Limit: query.PageLimit()}
+	for _, user := range f.users {
+		list.Users = append(list.Users, user)
+	}
+	list.Total = len(list.Users)
+	return list, nil
+}
+
+func (f *fakeUsers) DeleteUser(ctx context.Context, id string) error {
+	if _, ok := f.users[id]; !ok {
+		return types.ErrNotFound
+	}
+	delete(f.users, id)
+	return nil
+}
+
+func (f *fakeUsers) UserExists(ctx context.Context, email string) (bool, error) {
+	for _, user := range f.users {
+		if user.Email == email {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestClientAgreesWithServer(t *testing.T) {
+	svc := &fakeUsers{users: map[string]types.UserResponse{}}
+	srv := httptest.NewServer(httpserver.NewUserHandler(svc))
+	defer srv.Close()
+
+	ctx := context.Background()
+	client := httpclient.NewUserClient(srv.URL, srv.Client())
+	orgID := uuid.New()
+
+	created, err := client.CreateUser(ctx, types.UserCreateRequest{Email: "a@example.com", Role: "admin", OrgID: &orgID})
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	if created.ID != "u1" || created.OrgID == nil || *created.OrgID != orgID {
+		t.Errorf("CreateUser() = %+v", created)
+	}
+
+	if _, err := client.CreateUser(ctx, types.UserCreateRequest{Email: "not-an-email"}); !errors.Is(err, types.ErrValidation) {
+		t.Errorf("CreateUser(invalid) error = %v, want validation error", err)
+	}
+
+	got, err := client.GetUserByID(ctx, "u1")
+	if err != nil || got.Email != "a@example.com" {
+		t.Errorf("GetUserByID() = %+v, %v", got, err)
+	}
+
+	verified := true
+	list, err := client.ListUsers(ctx, types.UserListQuery{Page: 3, Limit: 5, Role: "admin", OrgID: &orgID, EmailVerified: &verified})
+	if err != nil {
+		t.Fatalf("ListUsers() error = %v", err)
+	}
+	if list.Total != 1 || list.Page != 3 || list.Limit != 5 {
+		t.Errorf("ListUsers() = %+v", list)
+	}
+	q := svc.lastQuery
+	if q.Page != 3 || q.Limit != 5 || q.Role != "admin" || q.OrgID == nil || *q.OrgID != orgID || q.EmailVerified == nil || !*q.EmailVerified {
+		t.Errorf("server received query %+v", q)
+	}
+
+	exists, err := client.UserExists(ctx, "a@example.com")
+	if err != nil || !exists {
+		t.Errorf("UserExists() = %v, %v", exists, err)
+	}
+
+	if err := client.DeleteUser(ctx, "u1"); err != nil {
+		t.Fatalf("DeleteUser() error = %v", err)
+	}
+	if _, err := client.GetUserByID(ctx, "u1"); !errors.Is(err, types.ErrNotFound) {
+		t.Errorf("GetUserByID(deleted) error = %v, want not found", err)
+	}
+}
+
+func writeEnvelope(t *testing.T, w http.ResponseWriter, status int, body interface{}) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		t.Errorf("encode response: %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/jonnyt98/atlas-shared/types"
+	"github.com/jonnyt98/atlas-shared/validate"
+)
+
+// maxBodyBytes limits the size of decoded request bodies
+const maxBodyBytes = 1 << 20
+
+// decodeJSON decodes and validates the request body into dst, writing an error response on failure
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err := decoder.Decode(dst); err != nil {
+		writeError(w, &types.APIError{
+			Code:    types.ErrorCodeBadRequest,
+			Message: "invalid request body",
+			Details: err.Error(),
+		})
+		return false
+	}
+	if err := validate.Validate(dst); err != nil {
+		writeError(w, err)
+		return false
+	}
+	return true
+}
+
+// writeData writes a successful APIResponse envelope
+func writeData(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, types.NewResponse(data))
+}
+
+// writeError writes an error APIResponse envelope with a status derived from the error code
+func writeError(w http.ResponseWriter, err error) {
+	apiErr := types.ToAPIError(err)
+	writeJSON(w, apiErr.HTTPStatus(), apiErr.Response())
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
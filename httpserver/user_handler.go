@@ -0,0 +1,334 @@
+package httpserver
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/jonnyt98/atlas-shared/contracts"
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// userHandler exposes a UserServiceClient implementation over HTTP
+type userHandler struct {
+	svc     contracts.UserServiceClient
+	onError func(error)
+}
+
+// NewUserHandler mounts svc on the routes defined in contracts/user_routes.go
+func NewUserHandler(svc contracts.UserServiceClient) http.Handler {
+	return NewUserHandlerWithErrorLog(svc, nil)
+}
+
+// NewUserHandlerWithErrorLog is NewUserHandler with server errors reported to onError.
+// Clients only see a generic message for them, so onError receives the original error for logging.
+func NewUserHandlerWithErrorLog(svc contracts.UserServiceClient, onError func(error)) http.Handler {
+	h := &userHandler{svc: svc, onError: onError}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc(contracts.UserRouteCreate, h.createUser)
+	mux.HandleFunc(contracts.UserRouteList, h.listUsers)
+	mux.HandleFunc(contracts.UserRouteGetByID, h.getUserByID)
+	mux.HandleFunc(contracts.UserRouteGetByEmail, h.getUserByEmail)
+	mux.HandleFunc(contracts.UserRouteGetByGoogleID, h.getUserByGoogleID)
+	mux.HandleFunc(contracts.UserRouteUpdate, h.updateUser)
+	mux.HandleFunc(contracts.UserRouteDelete, h.deleteUser)
+	mux.HandleFunc(contracts.UserRouteUpdateEmailVerification, h.updateEmailVerification)
+	mux.HandleFunc(contracts.UserRouteVerifyEmail, h.verifyEmail)
+	mux.HandleFunc(contracts.UserRouteResendVerification, h.resendVerification)
+	mux.HandleFunc(contracts.UserRouteAuthenticate, h.authenticateUser)
+	mux.HandleFunc(contracts.UserRouteLinkGoogleAccount, h.linkGoogleAccount)
+	mux.HandleFunc(contracts.UserRouteUpdatePassword, h.updatePassword)
+	mux.HandleFunc(contracts.UserRouteUpdateSubscription, h.updateSubscription)
+	mux.HandleFunc(contracts.UserRouteGetSubscription, h.getSubscription)
+	mux.HandleFunc(contracts.UserRouteCancelSubscription, h.cancelSubscription)
+	mux.HandleFunc(contracts.UserRouteExists, h.userExists)
+	mux.HandleFunc(contracts.UserRouteHealth, h.health)
+
+	return mux
+}
+
+func (h *userHandler) createUser(w http.ResponseWriter, r *http.Request) {
+	var req types.UserCreateRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	user, err := h.svc.CreateUser(r.Context(), req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeData(w, http.StatusCreated, user)
+}
+
+func (h *userHandler) listUsers(w http.ResponseWriter, r *http.Request) {
+	query, err := parseUserListQuery(r.URL.Query())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	list, err := h.svc.ListUsers(r.Context(), query)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, list)
+}
+
+func (h *userHandler) getUserByID(w http.ResponseWriter, r *http.Request) {
+	user, err := h.svc.GetUserByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, user)
+}
+
+func (h *userHandler) getUserByEmail(w http.ResponseWriter, r *http.Request) {
+	email, ok := requireQuery(w, r, "email")
+	if !ok {
+		return
+	}
+	user, err := h.svc.GetUserByEmail(r.Context(), email)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, user)
+}
+
+func (h *userHandler) getUserByGoogleID(w http.ResponseWriter, r *http.Request) {
+	googleID, ok := requireQuery(w, r, "google_id")
+	if !ok {
+		return
+	}
+	user, err := h.svc.GetUserByGoogleID(r.Context(), googleID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, user)
+}
+
+func (h *userHandler) updateUser(w http.ResponseWriter, r *http.Request) {
+	var req types.UserUpdateRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	user, err := h.svc.UpdateUser(r.Context(), r.PathValue("id"), req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, user)
+}
+
+func (h *userHandler) deleteUser(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.DeleteUser(r.Context(), r.PathValue("id")); err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, nil)
+}
+
+func (h *userHandler) updateEmailVerification(w http.ResponseWriter, r *http.Request) {
+	var req types.EmailVerificationRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	user, err := h.svc.UpdateEmailVerification(r.Context(), r.PathValue("id"), req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, user)
+}
+
+func (h *userHandler) verifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req types.VerifyEmailRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	resp, err := h.svc.VerifyEmail(r.Context(), req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, resp)
+}
+
+func (h *userHandler) resendVerification(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.ResendVerification(r.Context(), r.PathValue("id")); err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, nil)
+}
+
+func (h *userHandler) authenticateUser(w http.ResponseWriter, r *http.Request) {
+	var req types.AuthenticateRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	resp, err := h.svc.AuthenticateUser(r.Context(), req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, resp)
+}
+
+func (h *userHandler) linkGoogleAccount(w http.ResponseWriter, r *http.Request) {
+	var req types.GoogleLinkRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	user, err := h.svc.LinkGoogleAccount(r.Context(), r.PathValue("id"), req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, user)
+}
+
+func (h *userHandler) updatePassword(w http.ResponseWriter, r *http.Request) {
+	var req types.PasswordUpdateRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	user, err := h.svc.UpdatePassword(r.Context(), r.PathValue("id"), req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, user)
+}
+
+func (h *userHandler) updateSubscription(w http.ResponseWriter, r *http.Request) {
+	var req types.SubscriptionUpdateRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	user, err := h.svc.UpdateSubscription(r.Context(), r.PathValue("id"), req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, user)
+}
+
+func (h *userHandler) getSubscription(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.svc.GetSubscription(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, resp)
+}
+
+func (h *userHandler) cancelSubscription(w http.ResponseWriter, r *http.Request) {
+	user, err := h.svc.CancelSubscription(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, user)
+}
+
+func (h *userHandler) userExists(w http.ResponseWriter, r *http.Request) {
+	email, ok := requireQuery(w, r, "email")
+	if !ok {
+		return
+	}
+	exists, err := h.svc.UserExists(r.Context(), email)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, types.UserExistsResponse{Exists: exists})
+}
+
+func (h *userHandler) health(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.Health(r.Context()); err != nil {
+		h.report(err)
+		writeError(w, &types.APIError{
+			Code:    types.ErrorCodeServiceUnavailable,
+			Message: "user service unhealthy",
+		})
+		return
+	}
+	writeData(w, http.StatusOK, nil)
+}
+
+// writeError writes err like the package-level writeError, reporting server errors to onError first
+func (h *userHandler) writeError(w http.ResponseWriter, err error) {
+	if types.ToAPIError(err).HTTPStatus() >= http.StatusInternalServerError {
+		h.report(err)
+	}
+	writeError(w, err)
+}
+
+// report passes err to onError, if set
+func (h *userHandler) report(err error) {
+	if h.onError != nil {
+		h.onError(err)
+	}
+}
+
+// requireQuery reads a required query parameter, writing a validation error if it is missing
+func requireQuery(w http.ResponseWriter, r *http.Request, name string) (string, bool) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		writeError(w, &types.APIError{
+			Code:    types.ErrorCodeValidation,
+			Message: "missing query parameter",
+			Details: name,
+		})
+		return "", false
+	}
+	return value, true
+}
+
+// parseUserListQuery decodes UserListQuery from URL query parameters
+func parseUserListQuery(values url.Values) (types.UserListQuery, error) {
+	var query types.UserListQuery
+	var err error
+
+	if v := values.Get("page"); v != "" {
+		if query.Page, err = strconv.Atoi(v); err != nil {
+			return query, invalidQuery("page", err)
+		}
+	}
+	if v := values.Get("limit"); v != "" {
+		if query.Limit, err = strconv.Atoi(v); err != nil {
+			return query, invalidQuery("limit", err)
+		}
+	}
+	query.Cursor = values.Get("cursor")
+	query.Role = values.Get("role")
+	if v := values.Get("org_id"); v != "" {
+		orgID, err := uuid.Parse(v)
+		if err != nil {
+			return query, invalidQuery("org_id", err)
+		}
+		query.OrgID = &orgID
+	}
+	if v := values.Get("email_verified"); v != "" {
+		verified, err := strconv.ParseBool(v)
+		if err != nil {
+			return query, invalidQuery("email_verified", err)
+		}
+		query.EmailVerified = &verified
+	}
+
+	return query, nil
+}
+
+func invalidQuery(name string, err error) *types.APIError {
+	return &types.APIError{
+		Code:    types.ErrorCodeValidation,
+		Message: "invalid query parameter " + name,
+		Details: err.Error(),
+	}
+}
@@ -0,0 +1,377 @@
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jonnyt98/atlas-shared/contracts"
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// fakeUserService records the method each request reaches and fails it with err, if set
+type fakeUserService struct {
+	err    error
+	called string
+	id     string
+}
+
+var _ contracts.UserServiceClient = (*fakeUserService)(nil)
+
+func (f *fakeUserService) CreateUser(ctx context.Context, req types.UserCreateRequest) (*types.UserResponse, error) {
+	f.called = "CreateUser"
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &types.UserResponse{}, nil
+}
+
+func (f *fakeUserService) GetUserByID(ctx context.Context, id string) (*types.UserResponse, error) {
+	f.called = "GetUserByID"
+	f.id = id
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &types.UserResponse{}, nil
+}
+
+func (f *fakeUserService) GetUserByEmail(ctx context.Context, email string) (*types.UserResponse, error) {
+	f.called = "GetUserByEmail"
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &types.UserResponse{}, nil
+}
+
+func (f *fakeUserService) GetUserByGoogleID(ctx context.Context, googleID string) (*types.UserResponse, error) {
+	f.called = "GetUserByGoogleID"
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &types.UserResponse{}, nil
+}
+
+func (f *fakeUserService) UpdateUser(ctx context.Context, id string, req types.UserUpdateRequest) (*types.UserResponse, error) {
+	f.called = "UpdateUser"
+	f.id = id
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &types.UserResponse{}, nil
+}
+
+func (f *fakeUserService) DeleteUser(ctx context.Context, id string) error {
+	f.called = "DeleteUser"
+	f.id = id
+	return f.err
+}
+
+func (f *fakeUserService) ListUsers(ctx context.Context, query types.UserListQuery) (*types.UserListResponse, error) {
+	f.called = "ListUsers"
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &types.UserListResponse{}, nil
+}
+
+func (f *fakeUserService) UpdateEmailVerification(ctx context.Context, id string, req types.EmailVerificationRequest) (*types.UserResponse, error) {
+	f.called = "UpdateEmailVerification"
+	f.id = id
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &types.UserResponse{}, nil
+}
+
+func (f *fakeUserService) VerifyEmail(ctx context.Context, req types.VerifyEmailRequest) (*types.VerifyEmailResponse, error) {
+	f.called = "VerifyEmail"
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &types.VerifyEmailResponse{}, nil
+}
+
+func (f *fakeUserService) ResendVerification(ctx context.Context, id string) error {
+	f.called = "ResendVerification"
+	f.id = id
+	return f.err
+}
+
+func (f *fakeUserService) AuthenticateUser(ctx context.Context, req types.AuthenticateRequest) (*types.AuthenticateResponse, error) {
+	f.called = "AuthenticateUser"
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &types.AuthenticateResponse{}, nil
+}
+
+func (f *fakeUserService) LinkGoogleAccount(ctx context.Context, id string, req types.GoogleLinkRequest) (*types.UserResponse, error) {
+	f.called = "LinkGoogleAccount"
+	f.id = id
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &types.UserResponse{}, nil
+}
+
+func (f *fakeUserService) UpdatePassword(ctx context.Context, id string, req types.PasswordUpdateRequest) (*types.UserResponse, error) {
+	f.called = "UpdatePassword"
+	f.id = id
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &types.UserResponse{}, nil
+}
+
+func (f *fakeUserService) UpdateSubscription(ctx context.Context, id string, req types.SubscriptionUpdateRequest) (*types.UserResponse, error) {
+	f.called = "UpdateSubscription"
+	f.id = id
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &types.UserResponse{}, nil
+}
+
+func (f *fakeUserService) GetSubscription(ctx context.Context, id string) (*types.UserSubscriptionResponse, error) {
+	f.called = "GetSubscription"
+	f.id = id
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &types.UserSubscriptionResponse{}, nil
+}
+
+func (f *fakeUserService) CancelSubscription(ctx context.Context, id string) (*types.UserResponse, error) {
+	f.called = "CancelSubscription"
+	f.id = id
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &types.UserResponse{}, nil
+}
+
+func (f *fakeUserService) UserExists(ctx context.Context, email string) (bool, error) {
+	f.called = "UserExists"
+	if f.err != nil {
+		return false, f.err
+	}
+	return true, nil
+}
+
+func (f *fakeUserService) Health(ctx context.Context) error {
+	f.called = "Health"
+	return f.err
+}
+
+func serve(h http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return rec
+}
+
+func TestServerErrorsDoNotLeak(t *testing.T) {
+	const secret = "dial tcp 10.0.3.7:5432: connection refused"
+	raw := errors.New(secret)
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+	}{
+		{"service error", "/users/u1", http.StatusInternalServerError},
+		{"health", "/health", http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logged []error
+			h := NewUserHandlerWithErrorLog(&fakeUserService{err: raw}, func(err error) { logged = append(logged, err) })
+
+			rec := serve(h, http.MethodGet, tt.target, "")
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if strings.Contains(rec.Body.String(), "10.0.3.7") {
+				t.Errorf("body = %s, leaks the internal error", rec.Body.String())
+			}
+			if len(logged) != 1 || !errors.Is(logged[0], raw) {
+				t.Errorf("onError got %v, want the original error", logged)
+			}
+		})
+	}
+}
+
+func TestClientErrorsAreNotLogged(t *testing.T) {
+	var logged []error
+	h := NewUserHandlerWithErrorLog(&fakeUserService{err: types.ErrNotFound}, func(err error) { logged = append(logged, err) })
+
+	if rec := serve(h, http.MethodGet, "/users/u1", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+	if len(logged) != 0 {
+		t.Errorf("onError got %v for a client error", logged)
+	}
+}
+
+// decodeEnvelope decodes an APIResponse body, failing the test on malformed JSON
+func decodeEnvelope(t *testing.T, rec *httptest.ResponseRecorder) types.APIResponse {
+	t.Helper()
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	var resp types.APIResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("body %q is not an APIResponse: %v", rec.Body.String(), err)
+	}
+	return resp
+}
+
+func TestUserHandlerRoutes(t *testing.T) {
+	tests := []struct {
+		method     string
+		target     string
+		body       string
+		wantCall   string
+		wantID     string
+		wantStatus int
+	}{
+		{http.MethodPost, "/users", `{"email":"ada@example.com"}`, "CreateUser", "", http.StatusCreated},
+		{http.MethodGet, "/users?limit=10", "", "ListUsers", "", http.StatusOK},
+		{http.MethodGet, "/users/u1", "", "GetUserByID", "u1", http.StatusOK},
+		{http.MethodGet, "/users/by-email?email=ada@example.com", "", "GetUserByEmail", "", http.StatusOK},
+		{http.MethodGet, "/users/by-google-id?google_id=g1", "", "GetUserByGoogleID", "", http.StatusOK},
+		{http.MethodPatch, "/users/u1", `{"role":"admin"}`, "UpdateUser", "u1", http.StatusOK},
+		{http.MethodDelete, "/users/u1", "", "DeleteUser", "u1", http.StatusOK},
+		{http.MethodPut, "/users/u1/email-verification", `{"email_verified":true}`, "UpdateEmailVerification", "u1", http.StatusOK},
+		{http.MethodPost, "/users/verify-email", `{"token":"t1"}`, "VerifyEmail", "", http.StatusOK},
+		{http.MethodPost, "/users/u1/resend-verification", "", "ResendVerification", "u1", http.StatusOK},
+		{http.MethodPost, "/users/authenticate", `{"email":"ada@example.com","password":"pw"}`, "AuthenticateUser", "", http.StatusOK},
+		{http.MethodPost, "/users/u1/google", `{"google_id":"g1"}`, "LinkGoogleAccount", "u1", http.StatusOK},
+		{http.MethodPut, "/users/u1/password", `{"password_hash":"h"}`, "UpdatePassword", "u1", http.StatusOK},
+		{http.MethodPut, "/users/u1/subscription", `{"plan":"pro"}`, "UpdateSubscription", "u1", http.StatusOK},
+		{http.MethodGet, "/users/u1/subscription", "", "GetSubscription", "u1", http.StatusOK},
+		{http.MethodDelete, "/users/u1/subscription", "", "CancelSubscription", "u1", http.StatusOK},
+		{http.MethodGet, "/users/exists?email=ada@example.com", "", "UserExists", "", http.StatusOK},
+		{http.MethodGet, "/health", "", "Health", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			svc := &fakeUserService{}
+			rec := serve(NewUserHandler(svc), tt.method, tt.target, tt.body)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if svc.called != tt.wantCall || svc.id != tt.wantID {
+				t.Errorf("called %s(%q), want %s(%q)", svc.called, svc.id, tt.wantCall, tt.wantID)
+			}
+			if resp := decodeEnvelope(t, rec); !resp.Success || resp.Error != nil {
+				t.Errorf("envelope = %+v, want success", resp)
+			}
+		})
+	}
+}
+
+func TestUserHandlerRejectsUnknownRoutes(t *testing.T) {
+	tests := []struct {
+		method     string
+		target     string
+		wantStatus int
+	}{
+		{http.MethodPut, "/users", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/users/u1", http.StatusMethodNotAllowed},
+		{http.MethodPut, "/users/u1/google", http.StatusMethodNotAllowed},
+		{http.MethodPatch, "/users/u1/subscription", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/health", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/users/u1/unknown", http.StatusNotFound},
+		{http.MethodGet, "/accounts", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			svc := &fakeUserService{}
+			rec := serve(NewUserHandler(svc), tt.method, tt.target, "")
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if svc.called != "" {
+				t.Errorf("request reached %s", svc.called)
+			}
+		})
+	}
+}
+
+func TestUserHandlerBadRequests(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		body     string
+		wantCode string
+	}{
+		{"malformed JSON", http.MethodPost, "/users", `{"email":`, types.ErrorCodeBadRequest},
+		{"empty body", http.MethodPost, "/users/authenticate", "", types.ErrorCodeBadRequest},
+		{"wrong field type", http.MethodPatch, "/users/u1", `{"role":7}`, types.ErrorCodeBadRequest},
+		{"not an object", http.MethodPut, "/users/u1/password", `["h"]`, types.ErrorCodeBadRequest},
+		{"body too large", http.MethodPost, "/users/verify-email", `{"token":"` + strings.Repeat("a", maxBodyBytes) + `"}`, types.ErrorCodeBadRequest},
+		{"missing required field", http.MethodPost, "/users/u1/google", `{}`, types.ErrorCodeValidation},
+		{"invalid email", http.MethodPost, "/users", `{"email":"ada"}`, types.ErrorCodeValidation},
+		{"below minimum", http.MethodPut, "/users/u1/subscription", `{"seats":0}`, types.ErrorCodeValidation},
+		{"missing query parameter", http.MethodGet, "/users/by-email", "", types.ErrorCodeValidation},
+		{"invalid query parameter", http.MethodGet, "/users?page=two", "", types.ErrorCodeValidation},
+		{"invalid org_id", http.MethodGet, "/users?org_id=acme", "", types.ErrorCodeValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &fakeUserService{}
+			rec := serve(NewUserHandler(svc), tt.method, tt.target, tt.body)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400", rec.Code)
+			}
+			resp := decodeEnvelope(t, rec)
+			if resp.Success || resp.Error == nil || resp.Error.Code != tt.wantCode {
+				t.Errorf("envelope = %+v, want error code %s", resp, tt.wantCode)
+			}
+			if svc.called != "" {
+				t.Errorf("request reached %s", svc.called)
+			}
+		})
+	}
+}
+
+func TestWriteErrorMapping(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{"not found", types.ErrNotFound, http.StatusNotFound, types.ErrorCodeNotFound},
+		{"conflict", types.ErrConflict, http.StatusConflict, types.ErrorCodeConflict},
+		{"forbidden", types.ErrForbidden, http.StatusForbidden, types.ErrorCodeForbidden},
+		{"specific code keeps its status", types.ErrTokenExpired, http.StatusUnauthorized, types.AuthErrorTokenExpired},
+		{"invalid transition", types.ErrInvalidTransition, http.StatusConflict, types.ErrorCodeInvalidTransition},
+		{"wrapped", fmt.Errorf("update: %w", types.ErrQuotaExceeded), http.StatusForbidden, types.SubscriptionErrorQuotaExceeded},
+		{"auth error", &types.AuthError{Code: types.AuthErrorAccountLocked, Message: "locked"}, http.StatusForbidden, types.AuthErrorAccountLocked},
+		{"unknown code", &types.APIError{Code: "mystery"}, http.StatusInternalServerError, "mystery"},
+		{"plain error", errors.New("boom"), http.StatusInternalServerError, types.ErrorCodeInternalServer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			writeError(rec, tt.err)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			resp := decodeEnvelope(t, rec)
+			if resp.Success || resp.Error == nil || resp.Error.Code != tt.wantCode {
+				t.Errorf("envelope = %+v, want error code %s", resp, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+// Package keylock provides in-process mutual exclusion per string key.
+package keylock
+
+import (
+	"context"
+	"sync"
+)
+
+// Map holds one lock per key and forgets a key once nobody holds or waits for it.
+// The zero value is not usable; create one with New.
+type Map struct {
+	mu    sync.Mutex
+	locks map[string]*entry
+}
+
+type entry struct {
+	ch      chan struct{}
+	waiters int
+}
+
+// New creates an empty lock map
+func New() *Map {
+	return &Map{locks: map[string]*entry{}}
+}
+
+// Lock blocks until the key is free or ctx is done.
+// The returned unlock function is safe to call more than once.
+func (m *Map) Lock(ctx context.Context, key string) (func(), error) {
+	m.mu.Lock()
+	e, ok := m.locks[key]
+	if !ok {
+		e = &entry{ch: make(chan struct{}, 1)}
+		m.locks[key] = e
+	}
+	e.waiters++
+	m.mu.Unlock()
+
+	select {
+	case e.ch <- struct{}{}:
+	case <-ctx.Done():
+		m.leave(key, e)
+		return nil, ctx.Err()
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			<-e.ch
+			m.leave(key, e)
+		})
+	}, nil
+}
+
+// leave drops a waiter and forgets the key once nobody uses it
+func (m *Map) leave(key string, e *entry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e.waiters--
+	if e.waiters == 0 {
+		delete(m.locks, key)
+	}
+}
@@ -0,0 +1,100 @@
+package keylock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLockSerializesKey(t *testing.T) {
+	m := New()
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		holders int
+		maxSeen int
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := m.Lock(context.Background(), "k")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			holders++
+			if holders > maxSeen {
+				maxSeen = holders
+			}
+			mu.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mu.Lock()
+			holders--
+			mu.Unlock()
+			unlock()
+		}()
+	}
+	wg.Wait()
+
+	if maxSeen != 1 {
+		t.Errorf("%d goroutines held the lock at once", maxSeen)
+	}
+	if len(m.locks) != 0 {
+		t.Errorf("%d keys left after every lock was released", len(m.locks))
+	}
+}
+
+func TestLockKeysAreIndependent(t *testing.T) {
+	m := New()
+	unlockA, err := m.Lock(context.Background(), "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlockA()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	unlockB, err := m.Lock(ctx, "b")
+	if err != nil {
+		t.Fatalf("Lock(b) while a is held error = %v", err)
+	}
+	unlockB()
+}
+
+func TestLockHonorsContext(t *testing.T) {
+	m := New()
+	unlock, err := m.Lock(context.Background(), "k")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := m.Lock(ctx, "k"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Lock(held) error = %v, want deadline exceeded", err)
+	}
+
+	// A second unlock must not release a lock taken by someone else
+	unlock()
+	next, err := m.Lock(context.Background(), "k")
+	if err != nil {
+		t.Fatalf("Lock(after unlock) error = %v", err)
+	}
+	unlock()
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := m.Lock(ctx, "k"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Lock() after a repeated unlock error = %v, want deadline exceeded", err)
+	}
+
+	next()
+	if len(m.locks) != 0 {
+		t.Errorf("%d keys left after every lock was released", len(m.locks))
+	}
+}
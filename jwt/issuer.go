@@ -0,0 +1,111 @@
+package jwt
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// SigningKeySource supplies the key and key ID used to sign new tokens
+type SigningKeySource interface {
+	SigningKey() (string, Key, error)
+}
+
+// staticSigningKey signs every token with the same key and no key ID
+type staticSigningKey struct {
+	key Key
+}
+
+func (s staticSigningKey) SigningKey() (string, Key, error) {
+	return "", s.key, nil
+}
+
+// Issuer signs access and refresh tokens
+type Issuer struct {
+	keys       SigningKeySource
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+
+	// Now returns the current time; defaults to time.Now
+	Now func() time.Time
+}
+
+// NewIssuer creates an issuer that signs with key and stamps the given lifetimes
+func NewIssuer(key Key, accessTTL, refreshTTL time.Duration) *Issuer {
+	return NewKeySourceIssuer(staticSigningKey{key: key}, accessTTL, refreshTTL)
+}
+
+// NewKeySourceIssuer creates an issuer that signs with the current key of keys,
+// such as a KeyRing, and stamps its key ID into the kid header
+func NewKeySourceIssuer(keys SigningKeySource, accessTTL, refreshTTL time.Duration) *Issuer {
+	return &Issuer{
+		keys:       keys,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+	}
+}
+
+// Sign signs the claims as given, without filling in any timestamps
+func (i *Issuer) Sign(claims types.TokenClaims) (string, error) {
+	keyID, key, err := i.keys.SigningKey()
+	if err != nil {
+		return "", err
+	}
+	return encode(key, keyID, claims)
+}
+
+// IssueAccessToken signs a fresh access token for the user.
+// RefreshToken is left empty for refresh.Sessions to fill with a rotated opaque token.
+func (i *Issuer) IssueAccessToken(user types.UserResponse) (*types.AuthTokens, error) {
+	access := newClaims(user, types.TokenTypeAccess, i.now(), i.accessTTL)
+	accessToken, err := i.Sign(access)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: sign access token: %w", err)
+	}
+
+	return &types.AuthTokens{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(i.accessTTL / time.Second),
+		ExpiresAt:   time.Unix(access.ExpiresAt, 0).UTC(),
+	}, nil
+}
+
+// IssueTokens signs a fresh access and refresh token pair for the user.
+//
+// Deprecated: signed refresh tokens cannot be rotated or revoked. Use IssueAccessToken
+// with refresh.Sessions, which pairs access tokens with rotated opaque refresh tokens.
+func (i *Issuer) IssueTokens(user types.UserResponse) (*types.AuthTokens, error) {
+	tokens, err := i.IssueAccessToken(user)
+	if err != nil {
+		return nil, err
+	}
+
+	refresh := newClaims(user, types.TokenTypeRefresh, i.now(), i.refreshTTL)
+	tokens.RefreshToken, err = i.Sign(refresh)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: sign refresh token: %w", err)
+	}
+	return tokens, nil
+}
+
+func (i *Issuer) now() time.Time {
+	if i.Now != nil {
+		return i.Now()
+	}
+	return time.Now()
+}
+
+func newClaims(user types.UserResponse, tokenType string, now time.Time, ttl time.Duration) types.TokenClaims {
+	return types.TokenClaims{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Role:      user.Role,
+		TokenType: tokenType,
+		TokenID:   uuid.NewString(),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	}
+}
@@ -0,0 +1,192 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// JWK is a single public key in a JWKS document
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+	KeyID     string `json:"kid"`
+
+	// RSA public key parameters
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// OKP public key parameters
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set document
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewJWK describes the public half of key under the given key ID
+func NewJWK(keyID string, key Key) (*JWK, error) {
+	holder, ok := key.(interface{ Public() crypto.PublicKey })
+	if !ok {
+		return nil, fmt.Errorf("jwt: %s key %q has no public key to publish", key.Algorithm(), keyID)
+	}
+
+	jwk := &JWK{Use: "sig", Algorithm: string(key.Algorithm()), KeyID: keyID}
+	switch public := holder.Public().(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = encoding.EncodeToString(public.N.Bytes())
+		jwk.E = encoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = encoding.EncodeToString(public)
+	default:
+		return nil, fmt.Errorf("jwt: unsupported public key type %T", public)
+	}
+	return jwk, nil
+}
+
+// Key converts the JWK to a verify-only Key
+func (j JWK) Key() (Key, error) {
+	switch j.KeyType {
+	case "RSA":
+		n, err := encoding.DecodeString(j.N)
+		if err != nil {
+			return nil, fmt.Errorf("jwt: key %q: invalid modulus: %w", j.KeyID, err)
+		}
+		e, err := encoding.DecodeString(j.E)
+		if err != nil {
+			return nil, fmt.Errorf("jwt: key %q: invalid exponent: %w", j.KeyID, err)
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("jwt: key %q: exponent too large", j.KeyID)
+		}
+		return NewRS256PublicKey(&rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}), nil
+	case "OKP":
+		if j.Curve != "Ed25519" {
+			return nil, fmt.Errorf("jwt: key %q: unsupported curve %q", j.KeyID, j.Curve)
+		}
+		x, err := encoding.DecodeString(j.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("jwt: key %q: invalid Ed25519 public key", j.KeyID)
+		}
+		return NewEdDSAPublicKey(ed25519.PublicKey(x)), nil
+	default:
+		return nil, fmt.Errorf("jwt: key %q: unsupported key type %q", j.KeyID, j.KeyType)
+	}
+}
+
+// JWKSHandler serves the public keys of ring as a JWKS document
+func JWKSHandler(ring *KeyRing) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		set, err := ring.JWKS()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		_ = json.NewEncoder(w).Encode(set)
+	})
+}
+
+// JWKSProvider fetches a raw JWKS document
+type JWKSProvider func(ctx context.Context) ([]byte, error)
+
+// FileJWKSProvider reads a JWKS document from a file on every refresh
+func FileJWKSProvider(path string) JWKSProvider {
+	return func(ctx context.Context) ([]byte, error) {
+		return os.ReadFile(path)
+	}
+}
+
+// KeySet holds verification keys loaded from a JWKS document.
+// It implements KeyResolver and is safe for concurrent use.
+type KeySet struct {
+	provider JWKSProvider
+
+	mu   sync.RWMutex
+	keys map[string]Key
+}
+
+// NewKeySet creates an empty key set backed by provider; call Refresh or Run to load keys
+func NewKeySet(provider JWKSProvider) *KeySet {
+	return &KeySet{provider: provider, keys: map[string]Key{}}
+}
+
+// Refresh fetches and parses the JWKS document, replacing the current keys on success
+func (s *KeySet) Refresh(ctx context.Context) error {
+	raw, err := s.provider(ctx)
+	if err != nil {
+		return fmt.Errorf("jwt: fetch jwks: %w", err)
+	}
+
+	var set JWKS
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return fmt.Errorf("jwt: decode jwks: %w", err)
+	}
+
+	keys := make(map[string]Key, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.Key()
+		if err != nil {
+			return err
+		}
+		keys[jwk.KeyID] = key
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+	return nil
+}
+
+// Run refreshes the key set every interval until ctx is canceled.
+// Refresh errors are passed to onError, if set, and the previous keys stay in use.
+func (s *KeySet) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil && onError != nil && !errors.Is(err, context.Canceled) {
+				onError(err)
+			}
+		}
+	}
+}
+
+// ResolveKey returns the key with the given ID
+func (s *KeySet) ResolveKey(keyID string, alg Algorithm) (Key, error) {
+	s.mu.RLock()
+	key, ok := s.keys[keyID]
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, invalidToken("unknown key id")
+	}
+	if key.Algorithm() != alg {
+		return nil, invalidToken("unexpected signing algorithm")
+	}
+	return key, nil
+}
@@ -0,0 +1,208 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// handlerProvider fetches the JWKS document from handler in process
+func handlerProvider(handler http.Handler) JWKSProvider {
+	return func(ctx context.Context) ([]byte, error) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil).WithContext(ctx))
+		if rec.Code != http.StatusOK {
+			return nil, errors.New(rec.Body.String())
+		}
+		return rec.Body.Bytes(), nil
+	}
+}
+
+func TestJWKSRoundTrip(t *testing.T) {
+	keys := testKeys(t)
+	ring := NewKeyRing()
+	for i, id := range []string{"hs", "rs", "ed"} {
+		if err := ring.Add(id, keys[i]); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	set, err := ring.JWKS()
+	if err != nil {
+		t.Fatalf("JWKS() error = %v", err)
+	}
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var parsed JWKS
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	// The shared HS256 secret is never published
+	if len(parsed.Keys) != 2 || parsed.Keys[0].KeyID != "rs" || parsed.Keys[1].KeyID != "ed" {
+		t.Fatalf("JWKS() = %s, want the rs and ed keys", data)
+	}
+	if k := parsed.Keys[0]; k.KeyType != "RSA" || k.Algorithm != "RS256" || k.Use != "sig" || k.E != "AQAB" {
+		t.Errorf("RSA JWK = %+v", k)
+	}
+	if k := parsed.Keys[1]; k.KeyType != "OKP" || k.Curve != "Ed25519" || k.Algorithm != "EdDSA" || k.X == "" {
+		t.Errorf("Ed25519 JWK = %+v", k)
+	}
+
+	for i, jwk := range parsed.Keys {
+		signer := keys[i+1]
+		t.Run(jwk.KeyID, func(t *testing.T) {
+			key, err := jwk.Key()
+			if err != nil {
+				t.Fatalf("Key() error = %v", err)
+			}
+			if key.Algorithm() != signer.Algorithm() {
+				t.Errorf("Algorithm() = %s, want %s", key.Algorithm(), signer.Algorithm())
+			}
+			if _, err := key.Sign([]byte("input")); !errors.Is(err, ErrVerifyOnly) {
+				t.Errorf("Sign() error = %v, want ErrVerifyOnly", err)
+			}
+			signature, _ := signer.Sign([]byte("input"))
+			if err := key.Verify([]byte("input"), signature); err != nil {
+				t.Errorf("Verify() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestJWKRejectsInvalidKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		jwk  JWK
+	}{
+		{"unknown key type", JWK{KeyType: "EC", KeyID: "k"}},
+		{"unknown curve", JWK{KeyType: "OKP", Curve: "X25519", X: "AAAA", KeyID: "k"}},
+		{"short Ed25519 key", JWK{KeyType: "OKP", Curve: "Ed25519", X: "AAAA", KeyID: "k"}},
+		{"RSA modulus not base64", JWK{KeyType: "RSA", N: "***", E: "AQAB", KeyID: "k"}},
+		{"RSA exponent too large", JWK{KeyType: "RSA", N: "AQAB", E: "AQAAAAAAAAAA", KeyID: "k"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.jwk.Key(); err == nil {
+				t.Errorf("Key() error = nil")
+			}
+		})
+	}
+	if _, err := NewJWK("hs", NewHS256Key([]byte("secret"))); err == nil {
+		t.Error("NewJWK(HS256) error = nil")
+	}
+}
+
+func TestKeySetRefresh(t *testing.T) {
+	keys := testKeys(t)
+	ring, issuer, _ := testRing(t)
+	if err := ring.Add("k1", keys[1]); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	set := NewKeySet(handlerProvider(JWKSHandler(ring)))
+	v := NewKeySetVerifier(set)
+	v.Now = func() time.Time { return testNow }
+
+	old := issue(t, issuer)
+	if _, err := v.Verify(old, types.TokenTypeAccess); !errors.Is(err, types.ErrInvalidToken) {
+		t.Errorf("Verify() before Refresh error = %v, want invalid token", err)
+	}
+	if err := set.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if _, err := v.Verify(old, types.TokenTypeAccess); err != nil {
+		t.Errorf("Verify() after Refresh error = %v", err)
+	}
+
+	// A rotated key is only trusted once the set refreshes, and the retiring key stays trusted
+	if err := ring.Rotate("k2", keys[2]); err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	current := issue(t, issuer)
+	if _, err := v.Verify(current, types.TokenTypeAccess); !errors.Is(err, types.ErrInvalidToken) {
+		t.Errorf("Verify(k2) before Refresh error = %v, want invalid token", err)
+	}
+	if err := set.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	for name, token := range map[string]string{"k1": old, "k2": current} {
+		if _, err := v.Verify(token, types.TokenTypeAccess); err != nil {
+			t.Errorf("Verify(%s) error = %v", name, err)
+		}
+	}
+
+	// Removed keys disappear on the next refresh
+	if err := ring.Remove("k1"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if err := set.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if _, err := v.Verify(old, types.TokenTypeAccess); !errors.Is(err, types.ErrInvalidToken) {
+		t.Errorf("Verify(k1) after removal error = %v, want invalid token", err)
+	}
+	if _, err := set.ResolveKey("k2", RS256); !errors.Is(err, types.ErrInvalidToken) {
+		t.Errorf("ResolveKey(k2, RS256) error = %v, want invalid token", err)
+	}
+}
+
+func TestKeySetRefreshFailureKeepsKeys(t *testing.T) {
+	keys := testKeys(t)
+	ring, issuer, _ := testRing(t)
+	if err := ring.Add("k1", keys[2]); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	document, err := handlerProvider(JWKSHandler(ring))(context.Background())
+	if err != nil {
+		t.Fatalf("provider error = %v", err)
+	}
+
+	var fail error
+	set := NewKeySet(func(ctx context.Context) ([]byte, error) {
+		if fail != nil {
+			return nil, fail
+		}
+		return document, nil
+	})
+	if err := set.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		fail     error
+		document string
+	}{
+		{"provider error", errors.New("connection refused"), ""},
+		{"not JSON", nil, "<html>"},
+		{"invalid key", nil, `{"keys":[{"kty":"OKP","crv":"Ed25519","x":"AAAA","kid":"k1"}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fail = tt.fail
+			saved := document
+			if tt.document != "" {
+				document = []byte(tt.document)
+			}
+			defer func() { document = saved }()
+
+			if err := set.Refresh(context.Background()); err == nil {
+				t.Fatal("Refresh() error = nil")
+			}
+			v := NewKeySetVerifier(set)
+			v.Now = func() time.Time { return testNow }
+			if _, err := v.Verify(issue(t, issuer), types.TokenTypeAccess); err != nil {
+				t.Errorf("Verify() after a failed refresh error = %v", err)
+			}
+		})
+	}
+}
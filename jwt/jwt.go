@@ -0,0 +1,81 @@
+package jwt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// header is the JOSE header of a compact JWS
+type header struct {
+	Algorithm Algorithm `json:"alg"`
+	Type      string    `json:"typ,omitempty"`
+	KeyID     string    `json:"kid,omitempty"`
+}
+
+var encoding = base64.RawURLEncoding
+
+// encode builds a signed compact token for the claims
+func encode(key Key, keyID string, claims types.TokenClaims) (string, error) {
+	headerJSON, err := json.Marshal(header{Algorithm: key.Algorithm(), Type: "JWT", KeyID: keyID})
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	input := encoding.EncodeToString(headerJSON) + "." + encoding.EncodeToString(claimsJSON)
+	signature, err := key.Sign([]byte(input))
+	if err != nil {
+		return "", err
+	}
+	return input + "." + encoding.EncodeToString(signature), nil
+}
+
+// parsed holds the decoded, not yet verified, parts of a compact token
+type parsed struct {
+	header    header
+	claims    types.TokenClaims
+	input     []byte
+	signature []byte
+}
+
+// decode splits and decodes a compact token without verifying its signature
+func decode(token string) (*parsed, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, invalidToken("malformed token")
+	}
+
+	var p parsed
+	headerJSON, err := encoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, invalidToken("malformed header")
+	}
+	if err := json.Unmarshal(headerJSON, &p.header); err != nil {
+		return nil, invalidToken("malformed header")
+	}
+
+	claimsJSON, err := encoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, invalidToken("malformed claims")
+	}
+	if err := json.Unmarshal(claimsJSON, &p.claims); err != nil {
+		return nil, invalidToken("malformed claims")
+	}
+
+	p.signature, err = encoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, invalidToken("malformed signature")
+	}
+	p.input = []byte(parts[0] + "." + parts[1])
+	return &p, nil
+}
+
+func invalidToken(details string) error {
+	return types.ErrInvalidToken.WithDetails(details)
+}
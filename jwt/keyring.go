@@ -0,0 +1,164 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// KeyState describes whether a key in a KeyRing may still sign tokens
+type KeyState string
+
+const (
+	// KeyStateActive keys sign new tokens and verify existing ones
+	KeyStateActive KeyState = "active"
+	// KeyStateRetiring keys only verify tokens issued before rotation
+	KeyStateRetiring KeyState = "retiring"
+)
+
+// ErrNoSigningKey is returned when a KeyRing has no active key
+var ErrNoSigningKey = errors.New("jwt: key ring has no active signing key")
+
+type ringEntry struct {
+	id    string
+	key   Key
+	state KeyState
+}
+
+// KeyRing holds the active and retiring keys of a token issuer.
+// The most recently added active key signs new tokens; all keys verify.
+// It implements SigningKeySource and KeyResolver and is safe for concurrent use.
+type KeyRing struct {
+	mu      sync.RWMutex
+	entries []ringEntry
+}
+
+// NewKeyRing creates an empty key ring
+func NewKeyRing() *KeyRing {
+	return &KeyRing{}
+}
+
+// Add adds an active key under id, making it the signing key
+func (r *KeyRing) Add(id string, key Key) error {
+	if id == "" {
+		return errors.New("jwt: key ID is required")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, entry := range r.entries {
+		if entry.id == id {
+			return fmt.Errorf("jwt: key %q already in ring", id)
+		}
+	}
+	r.entries = append(r.entries, ringEntry{id: id, key: key, state: KeyStateActive})
+	return nil
+}
+
+// Retire stops the key from signing while keeping it available for verification
+func (r *KeyRing) Retire(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.entries {
+		if r.entries[i].id == id {
+			r.entries[i].state = KeyStateRetiring
+			return nil
+		}
+	}
+	return fmt.Errorf("jwt: key %q not in ring", id)
+}
+
+// Remove drops the key entirely, invalidating tokens it signed
+func (r *KeyRing) Remove(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.entries {
+		if r.entries[i].id == id {
+			r.entries = append(r.entries[:i], r.entries[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("jwt: key %q not in ring", id)
+}
+
+// Rotate adds a new signing key and retires every previously active key
+func (r *KeyRing) Rotate(id string, key Key) error {
+	if err := r.Add(id, key); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.entries {
+		if r.entries[i].id != id && r.entries[i].state == KeyStateActive {
+			r.entries[i].state = KeyStateRetiring
+		}
+	}
+	return nil
+}
+
+// State returns the state of the key with the given ID
+func (r *KeyRing) State(id string) (KeyState, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, entry := range r.entries {
+		if entry.id == id {
+			return entry.state, true
+		}
+	}
+	return "", false
+}
+
+// SigningKey returns the most recently added active key
+func (r *KeyRing) SigningKey() (string, Key, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		if r.entries[i].state == KeyStateActive {
+			return r.entries[i].id, r.entries[i].key, nil
+		}
+	}
+	return "", nil, ErrNoSigningKey
+}
+
+// ResolveKey returns the active or retiring key with the given ID
+func (r *KeyRing) ResolveKey(keyID string, alg Algorithm) (Key, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, entry := range r.entries {
+		if entry.id == keyID {
+			if entry.key.Algorithm() != alg {
+				return nil, invalidToken("unexpected signing algorithm")
+			}
+			return entry.key, nil
+		}
+	}
+	return nil, invalidToken("unknown key id")
+}
+
+// JWKS returns the public keys in the ring as a JWKS document.
+// Symmetric HS256 keys are never published.
+func (r *KeyRing) JWKS() (*JWKS, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	set := &JWKS{Keys: []JWK{}}
+	for _, entry := range r.entries {
+		if entry.key.Algorithm() == HS256 {
+			continue
+		}
+		jwk, err := NewJWK(entry.id, entry.key)
+		if err != nil {
+			return nil, err
+		}
+		set.Keys = append(set.Keys, *jwk)
+	}
+	return set, nil
+}
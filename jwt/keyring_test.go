@@ -0,0 +1,149 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// testRing returns a key ring and verifier pinned to testNow
+func testRing(t *testing.T) (*KeyRing, *Issuer, *Verifier) {
+	t.Helper()
+	ring := NewKeyRing()
+	issuer := NewKeySourceIssuer(ring, 15*time.Minute, time.Hour)
+	issuer.Now = func() time.Time { return testNow }
+	v := NewKeySetVerifier(ring)
+	v.Now = func() time.Time { return testNow }
+	return ring, issuer, v
+}
+
+func issue(t *testing.T, issuer *Issuer) string {
+	t.Helper()
+	tokens, err := issuer.IssueAccessToken(testUser)
+	if err != nil {
+		t.Fatalf("IssueAccessToken() error = %v", err)
+	}
+	return tokens.AccessToken
+}
+
+func tokenKeyID(t *testing.T, token string) string {
+	t.Helper()
+	p, err := decode(token)
+	if err != nil {
+		t.Fatalf("decode() error = %v", err)
+	}
+	return p.header.KeyID
+}
+
+func TestKeyRingRotateAndRetire(t *testing.T) {
+	keys := testKeys(t)
+	ring, issuer, v := testRing(t)
+
+	if _, _, err := ring.SigningKey(); !errors.Is(err, ErrNoSigningKey) {
+		t.Fatalf("SigningKey() on an empty ring error = %v", err)
+	}
+	if err := ring.Add("k1", keys[1]); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	old := issue(t, issuer)
+	if kid := tokenKeyID(t, old); kid != "k1" {
+		t.Fatalf("kid = %q, want k1", kid)
+	}
+
+	if err := ring.Rotate("k2", keys[2]); err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	if state, _ := ring.State("k1"); state != KeyStateRetiring {
+		t.Errorf("State(k1) = %s after rotation, want retiring", state)
+	}
+	current := issue(t, issuer)
+	if kid := tokenKeyID(t, current); kid != "k2" {
+		t.Errorf("kid = %q after rotation, want k2", kid)
+	}
+
+	// The retiring key still verifies the tokens it signed
+	for name, token := range map[string]string{"old": old, "current": current} {
+		if _, err := v.Verify(token, types.TokenTypeAccess); err != nil {
+			t.Errorf("Verify(%s) error = %v", name, err)
+		}
+	}
+
+	if err := ring.Remove("k1"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := v.Verify(old, types.TokenTypeAccess); !errors.Is(err, types.ErrInvalidToken) {
+		t.Errorf("Verify(old) after removal error = %v, want invalid token", err)
+	}
+	if _, err := v.Verify(current, types.TokenTypeAccess); err != nil {
+		t.Errorf("Verify(current) after removal error = %v", err)
+	}
+
+	// Retiring the only active key leaves nothing to sign with
+	if err := ring.Retire("k2"); err != nil {
+		t.Fatalf("Retire() error = %v", err)
+	}
+	if _, err := issuer.IssueAccessToken(testUser); !errors.Is(err, ErrNoSigningKey) {
+		t.Errorf("IssueAccessToken() with no active key error = %v", err)
+	}
+	if _, err := v.Verify(current, types.TokenTypeAccess); err != nil {
+		t.Errorf("Verify(current) after retiring error = %v", err)
+	}
+}
+
+func TestKeyRingErrors(t *testing.T) {
+	keys := testKeys(t)
+	ring := NewKeyRing()
+
+	if err := ring.Add("", keys[0]); err == nil {
+		t.Error("Add() without a key ID error = nil")
+	}
+	if err := ring.Add("k1", keys[0]); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := ring.Add("k1", keys[1]); err == nil {
+		t.Error("Add() with a duplicate key ID error = nil")
+	}
+	if err := ring.Rotate("k1", keys[1]); err == nil {
+		t.Error("Rotate() with a duplicate key ID error = nil")
+	}
+	if state, _ := ring.State("k1"); state != KeyStateActive {
+		t.Errorf("State(k1) = %s after a failed rotation, want active", state)
+	}
+	for name, err := range map[string]error{"Retire": ring.Retire("k9"), "Remove": ring.Remove("k9")} {
+		if err == nil {
+			t.Errorf("%s(unknown) error = nil", name)
+		}
+	}
+	if _, ok := ring.State("k9"); ok {
+		t.Error("State(unknown) ok = true")
+	}
+}
+
+func TestKeyRingRejectsUnknownKeyID(t *testing.T) {
+	keys := testKeys(t)
+	ring, _, v := testRing(t)
+	if err := ring.Add("k1", keys[2]); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	other := NewEdDSAKey(ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)))
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"unknown kid", rawToken(t, other, header{Algorithm: EdDSA, KeyID: "k2"}, testClaims(types.TokenTypeAccess, testNow, time.Hour))},
+		{"missing kid", rawToken(t, keys[2], header{Algorithm: EdDSA}, testClaims(types.TokenTypeAccess, testNow, time.Hour))},
+		{"known kid, other algorithm", rawToken(t, keys[0], header{Algorithm: HS256, KeyID: "k1"}, testClaims(types.TokenTypeAccess, testNow, time.Hour))},
+		{"known kid, other key", rawToken(t, other, header{Algorithm: EdDSA, KeyID: "k1"}, testClaims(types.TokenTypeAccess, testNow, time.Hour))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := v.Verify(tt.token, types.TokenTypeAccess); !errors.Is(err, types.ErrInvalidToken) {
+				t.Errorf("Verify() error = %v, want invalid token", err)
+			}
+		})
+	}
+}
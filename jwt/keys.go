@@ -0,0 +1,130 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+)
+
+// Algorithm identifies a JWS signing algorithm
+type Algorithm string
+
+// Supported signing algorithms
+const (
+	HS256 Algorithm = "HS256"
+	RS256 Algorithm = "RS256"
+	EdDSA Algorithm = "EdDSA"
+)
+
+// ErrVerifyOnly is returned when signing with a key that only holds public material
+var ErrVerifyOnly = errors.New("jwt: key cannot sign, it only holds a public key")
+
+// errBadSignature is returned by Key.Verify when the signature does not match
+var errBadSignature = errors.New("jwt: signature mismatch")
+
+// Key signs and verifies token signing inputs with a single algorithm
+type Key interface {
+	Algorithm() Algorithm
+	Sign(input []byte) ([]byte, error)
+	Verify(input, signature []byte) error
+}
+
+// hmacKey implements HS256
+type hmacKey struct {
+	secret []byte
+}
+
+// NewHS256Key creates an HMAC-SHA256 key from a shared secret
+func NewHS256Key(secret []byte) Key {
+	return &hmacKey{secret: append([]byte(nil), secret...)}
+}
+
+func (k *hmacKey) Algorithm() Algorithm { return HS256 }
+
+func (k *hmacKey) Sign(input []byte) ([]byte, error) {
+	mac := hmac.New(sha256.New, k.secret)
+	mac.Write(input)
+	return mac.Sum(nil), nil
+}
+
+func (k *hmacKey) Verify(input, signature []byte) error {
+	expected, _ := k.Sign(input)
+	if !hmac.Equal(expected, signature) {
+		return errBadSignature
+	}
+	return nil
+}
+
+// rsaKey implements RS256
+type rsaKey struct {
+	private *rsa.PrivateKey
+	public  *rsa.PublicKey
+}
+
+// NewRS256Key creates an RSA PKCS#1 v1.5 SHA-256 signing key
+func NewRS256Key(private *rsa.PrivateKey) Key {
+	return &rsaKey{private: private, public: &private.PublicKey}
+}
+
+// NewRS256PublicKey creates an RS256 key that can only verify
+func NewRS256PublicKey(public *rsa.PublicKey) Key {
+	return &rsaKey{public: public}
+}
+
+func (k *rsaKey) Algorithm() Algorithm { return RS256 }
+
+func (k *rsaKey) Public() crypto.PublicKey { return k.public }
+
+func (k *rsaKey) Sign(input []byte) ([]byte, error) {
+	if k.private == nil {
+		return nil, ErrVerifyOnly
+	}
+	digest := sha256.Sum256(input)
+	return rsa.SignPKCS1v15(rand.Reader, k.private, crypto.SHA256, digest[:])
+}
+
+func (k *rsaKey) Verify(input, signature []byte) error {
+	digest := sha256.Sum256(input)
+	if err := rsa.VerifyPKCS1v15(k.public, crypto.SHA256, digest[:], signature); err != nil {
+		return errBadSignature
+	}
+	return nil
+}
+
+// ed25519Key implements EdDSA over Ed25519
+type ed25519Key struct {
+	private ed25519.PrivateKey
+	public  ed25519.PublicKey
+}
+
+// NewEdDSAKey creates an Ed25519 signing key
+func NewEdDSAKey(private ed25519.PrivateKey) Key {
+	return &ed25519Key{private: private, public: private.Public().(ed25519.PublicKey)}
+}
+
+// NewEdDSAPublicKey creates an EdDSA key that can only verify
+func NewEdDSAPublicKey(public ed25519.PublicKey) Key {
+	return &ed25519Key{public: public}
+}
+
+func (k *ed25519Key) Algorithm() Algorithm { return EdDSA }
+
+func (k *ed25519Key) Public() crypto.PublicKey { return k.public }
+
+func (k *ed25519Key) Sign(input []byte) ([]byte, error) {
+	if k.private == nil {
+		return nil, ErrVerifyOnly
+	}
+	return ed25519.Sign(k.private, input), nil
+}
+
+func (k *ed25519Key) Verify(input, signature []byte) error {
+	if !ed25519.Verify(k.public, input, signature) {
+		return errBadSignature
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package jwt
+
+import (
+	"time"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// KeyResolver finds the verification key for a token header
+type KeyResolver interface {
+	ResolveKey(keyID string, alg Algorithm) (Key, error)
+}
+
+// singleKey resolves every token to the same key regardless of key ID
+type singleKey struct {
+	key Key
+}
+
+func (s singleKey) ResolveKey(_ string, alg Algorithm) (Key, error) {
+	if alg != s.key.Algorithm() {
+		return nil, invalidToken("unexpected signing algorithm")
+	}
+	return s.key, nil
+}
+
+// Verifier checks token signatures, lifetimes and token types
+type Verifier struct {
+	keys KeyResolver
+
+	// Leeway allows for clock skew when checking exp and iat
+	Leeway time.Duration
+	// Now returns the current time; defaults to time.Now
+	Now func() time.Time
+}
+
+// NewVerifier creates a verifier that accepts tokens signed by key
+func NewVerifier(key Key) *Verifier {
+	return &Verifier{keys: singleKey{key: key}}
+}
+
+// NewKeySetVerifier creates a verifier that picks the key for each token by its kid header,
+// using a KeyRing or a KeySet loaded from a JWKS document
+func NewKeySetVerifier(keys KeyResolver) *Verifier {
+	return &Verifier{keys: keys}
+}
+
+// Verify checks the token and returns its claims.
+// tokenType must be types.TokenTypeAccess or types.TokenTypeRefresh.
+// Failures are returned as types.ErrInvalidToken or types.ErrTokenExpired.
+func (v *Verifier) Verify(token, tokenType string) (*types.TokenClaims, error) {
+	p, err := decode(token)
+	if err != nil {
+		return nil, err
+	}
+	key, err := v.keys.ResolveKey(p.header.KeyID, p.header.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	if err := key.Verify(p.input, p.signature); err != nil {
+		return nil, invalidToken("invalid signature")
+	}
+	if err := v.checkClaims(&p.claims, tokenType); err != nil {
+		return nil, err
+	}
+	return &p.claims, nil
+}
+
+// Session verifies an access token and returns the session it describes
+func (v *Verifier) Session(token string) (*types.SessionInfo, error) {
+	claims, err := v.Verify(token, types.TokenTypeAccess)
+	if err != nil {
+		return nil, err
+	}
+	return SessionFromClaims(claims), nil
+}
+
+// SessionFromClaims converts verified claims to session information
+func SessionFromClaims(claims *types.TokenClaims) *types.SessionInfo {
+	return &types.SessionInfo{
+		UserID:    claims.UserID,
+		Email:     claims.Email,
+		Role:      claims.Role,
+		IssuedAt:  time.Unix(claims.IssuedAt, 0).UTC(),
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0).UTC(),
+	}
+}
+
+func (v *Verifier) checkClaims(claims *types.TokenClaims, tokenType string) error {
+	if claims.TokenType != tokenType {
+		return invalidToken("expected " + tokenType + " token")
+	}
+	if claims.UserID == "" {
+		return invalidToken("missing user_id")
+	}
+	if claims.ExpiresAt == 0 {
+		return invalidToken("missing exp")
+	}
+	if claims.IssuedAt == 0 {
+		return invalidToken("missing iat")
+	}
+
+	now := v.now()
+	if now.Add(v.Leeway).Unix() < claims.IssuedAt {
+		return invalidToken("token issued in the future")
+	}
+	if now.Add(-v.Leeway).Unix() >= claims.ExpiresAt {
+		return types.ErrTokenExpired
+	}
+	return nil
+}
+
+func (v *Verifier) now() time.Time {
+	if v.Now != nil {
+		return v.Now()
+	}
+	return time.Now()
+}
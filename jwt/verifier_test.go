@@ -0,0 +1,292 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+var testNow = time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+var testUser = types.UserResponse{ID: "u1", Email: "ada@example.com", Role: "admin"}
+
+// testKeys returns a fresh signing key for every supported algorithm
+func testKeys(t *testing.T) []Key {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("ed25519.GenerateKey() error = %v", err)
+	}
+	return []Key{
+		NewHS256Key([]byte("0123456789abcdef0123456789abcdef")),
+		NewRS256Key(rsaKey),
+		NewEdDSAKey(edKey),
+	}
+}
+
+// rawToken builds a compact token from an arbitrary header, signed by key when it is not nil
+func rawToken(t *testing.T, key Key, h header, claims types.TokenClaims) string {
+	t.Helper()
+	headerJSON, _ := json.Marshal(h)
+	claimsJSON, _ := json.Marshal(claims)
+	input := encoding.EncodeToString(headerJSON) + "." + encoding.EncodeToString(claimsJSON)
+	if key == nil {
+		return input + "."
+	}
+	signature, err := key.Sign([]byte(input))
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	return input + "." + encoding.EncodeToString(signature)
+}
+
+func testClaims(tokenType string, issuedAt time.Time, ttl time.Duration) types.TokenClaims {
+	return newClaims(testUser, tokenType, issuedAt, ttl)
+}
+
+func TestVerifierAlgorithms(t *testing.T) {
+	for _, key := range testKeys(t) {
+		alg := key.Algorithm()
+		t.Run(string(alg), func(t *testing.T) {
+			other := NewHS256Key([]byte("another secret, another service!"))
+			if alg == HS256 {
+				other = NewEdDSAKey(ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)))
+			}
+
+			tests := []struct {
+				name      string
+				token     func(t *testing.T) string
+				tokenType string
+				wantErr   error
+			}{
+				{"valid access token", func(t *testing.T) string {
+					return rawToken(t, key, header{Algorithm: alg, Type: "JWT"}, testClaims(types.TokenTypeAccess, testNow, time.Hour))
+				}, types.TokenTypeAccess, nil},
+				{"valid refresh token", func(t *testing.T) string {
+					return rawToken(t, key, header{Algorithm: alg}, testClaims(types.TokenTypeRefresh, testNow, time.Hour))
+				}, types.TokenTypeRefresh, nil},
+				{"alg none", func(t *testing.T) string {
+					return rawToken(t, nil, header{Algorithm: "none"}, testClaims(types.TokenTypeAccess, testNow, time.Hour))
+				}, types.TokenTypeAccess, types.ErrInvalidToken},
+				{"alg header mismatch", func(t *testing.T) string {
+					return rawToken(t, other, header{Algorithm: other.Algorithm()}, testClaims(types.TokenTypeAccess, testNow, time.Hour))
+				}, types.TokenTypeAccess, types.ErrInvalidToken},
+				{"alg header relabelled", func(t *testing.T) string {
+					return rawToken(t, key, header{Algorithm: other.Algorithm()}, testClaims(types.TokenTypeAccess, testNow, time.Hour))
+				}, types.TokenTypeAccess, types.ErrInvalidToken},
+				{"bad signature", func(t *testing.T) string {
+					token := rawToken(t, key, header{Algorithm: alg}, testClaims(types.TokenTypeAccess, testNow, time.Hour))
+					i := strings.LastIndex(token, ".") + 1
+					sig, _ := encoding.DecodeString(token[i:])
+					sig[0] ^= 0xff
+					return token[:i] + encoding.EncodeToString(sig)
+				}, types.TokenTypeAccess, types.ErrInvalidToken},
+				{"tampered claims", func(t *testing.T) string {
+					token := rawToken(t, key, header{Algorithm: alg}, testClaims(types.TokenTypeAccess, testNow, time.Hour))
+					parts := strings.Split(token, ".")
+					forged := testClaims(types.TokenTypeAccess, testNow, time.Hour)
+					forged.Role = "owner"
+					claimsJSON, _ := json.Marshal(forged)
+					return parts[0] + "." + encoding.EncodeToString(claimsJSON) + "." + parts[2]
+				}, types.TokenTypeAccess, types.ErrInvalidToken},
+				{"signed by another key", func(t *testing.T) string {
+					return rawToken(t, sameAlgorithmKey(t, alg), header{Algorithm: alg}, testClaims(types.TokenTypeAccess, testNow, time.Hour))
+				}, types.TokenTypeAccess, types.ErrInvalidToken},
+				{"expired", func(t *testing.T) string {
+					return rawToken(t, key, header{Algorithm: alg}, testClaims(types.TokenTypeAccess, testNow.Add(-2*time.Hour), time.Hour))
+				}, types.TokenTypeAccess, types.ErrTokenExpired},
+				{"expires now", func(t *testing.T) string {
+					return rawToken(t, key, header{Algorithm: alg}, testClaims(types.TokenTypeAccess, testNow.Add(-time.Hour), time.Hour))
+				}, types.TokenTypeAccess, types.ErrTokenExpired},
+				{"issued in the future", func(t *testing.T) string {
+					return rawToken(t, key, header{Algorithm: alg}, testClaims(types.TokenTypeAccess, testNow.Add(time.Minute), time.Hour))
+				}, types.TokenTypeAccess, types.ErrInvalidToken},
+				{"refresh token used as access token", func(t *testing.T) string {
+					return rawToken(t, key, header{Algorithm: alg}, testClaims(types.TokenTypeRefresh, testNow, time.Hour))
+				}, types.TokenTypeAccess, types.ErrInvalidToken},
+				{"access token used as refresh token", func(t *testing.T) string {
+					return rawToken(t, key, header{Algorithm: alg}, testClaims(types.TokenTypeAccess, testNow, time.Hour))
+				}, types.TokenTypeRefresh, types.ErrInvalidToken},
+				{"missing user", func(t *testing.T) string {
+					claims := testClaims(types.TokenTypeAccess, testNow, time.Hour)
+					claims.UserID = ""
+					return rawToken(t, key, header{Algorithm: alg}, claims)
+				}, types.TokenTypeAccess, types.ErrInvalidToken},
+				{"missing exp", func(t *testing.T) string {
+					claims := testClaims(types.TokenTypeAccess, testNow, time.Hour)
+					claims.ExpiresAt = 0
+					return rawToken(t, key, header{Algorithm: alg}, claims)
+				}, types.TokenTypeAccess, types.ErrInvalidToken},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					v := NewVerifier(key)
+					v.Now = func() time.Time { return testNow }
+
+					claims, err := v.Verify(tt.token(t), tt.tokenType)
+					if tt.wantErr == nil {
+						if err != nil {
+							t.Fatalf("Verify() error = %v", err)
+						}
+						if claims.UserID != testUser.ID || claims.TokenType != tt.tokenType {
+							t.Errorf("claims = %+v", claims)
+						}
+						return
+					}
+					if !errors.Is(err, tt.wantErr) {
+						t.Errorf("Verify() error = %v, want %v", err, tt.wantErr)
+					}
+					if tt.wantErr == types.ErrInvalidToken && errors.Is(err, types.ErrTokenExpired) {
+						t.Errorf("Verify() error = %v, want it not to report expiry", err)
+					}
+				})
+			}
+		})
+	}
+}
+
+// sameAlgorithmKey returns a new key with the given algorithm that does not match any key from testKeys
+func sameAlgorithmKey(t *testing.T, alg Algorithm) Key {
+	t.Helper()
+	switch alg {
+	case HS256:
+		return NewHS256Key([]byte("a different shared secret value"))
+	case RS256:
+		private, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatalf("rsa.GenerateKey() error = %v", err)
+		}
+		return NewRS256Key(private)
+	default:
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatalf("ed25519.GenerateKey() error = %v", err)
+		}
+		return NewEdDSAKey(private)
+	}
+}
+
+func TestVerifierMalformedTokens(t *testing.T) {
+	key := NewHS256Key([]byte("0123456789abcdef0123456789abcdef"))
+	valid := rawToken(t, key, header{Algorithm: HS256}, testClaims(types.TokenTypeAccess, testNow, time.Hour))
+	parts := strings.Split(valid, ".")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"one segment", parts[0]},
+		{"two segments", parts[0] + "." + parts[1]},
+		{"four segments", valid + "." + parts[2]},
+		{"header not base64", "!!!." + parts[1] + "." + parts[2]},
+		{"header not JSON", encoding.EncodeToString([]byte("alg")) + "." + parts[1] + "." + parts[2]},
+		{"claims not base64", parts[0] + ".***." + parts[2]},
+		{"claims not JSON", parts[0] + "." + encoding.EncodeToString([]byte("[1,2]")) + "." + parts[2]},
+		{"signature not base64", parts[0] + "." + parts[1] + ".%%%"},
+		{"padded base64", parts[0] + "=." + parts[1] + "." + parts[2]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(key)
+			v.Now = func() time.Time { return testNow }
+			if _, err := v.Verify(tt.token, types.TokenTypeAccess); !errors.Is(err, types.ErrInvalidToken) {
+				t.Errorf("Verify(%q) error = %v, want invalid token", tt.token, err)
+			}
+		})
+	}
+}
+
+func TestVerifierLeeway(t *testing.T) {
+	key := NewHS256Key([]byte("0123456789abcdef0123456789abcdef"))
+	v := NewVerifier(key)
+	v.Leeway = time.Minute
+
+	expired := rawToken(t, key, header{Algorithm: HS256}, testClaims(types.TokenTypeAccess, testNow.Add(-time.Hour-30*time.Second), time.Hour))
+	early := rawToken(t, key, header{Algorithm: HS256}, testClaims(types.TokenTypeAccess, testNow.Add(30*time.Second), time.Hour))
+
+	v.Now = func() time.Time { return testNow }
+	for name, token := range map[string]string{"expired within leeway": expired, "issued early within leeway": early} {
+		if _, err := v.Verify(token, types.TokenTypeAccess); err != nil {
+			t.Errorf("%s: Verify() error = %v", name, err)
+		}
+	}
+
+	v.Now = func() time.Time { return testNow.Add(time.Minute) }
+	if _, err := v.Verify(expired, types.TokenTypeAccess); !errors.Is(err, types.ErrTokenExpired) {
+		t.Errorf("Verify() past the leeway error = %v, want token expired", err)
+	}
+}
+
+func TestIssuerRoundTrip(t *testing.T) {
+	for _, key := range testKeys(t) {
+		t.Run(string(key.Algorithm()), func(t *testing.T) {
+			issuer := NewIssuer(key, 15*time.Minute, 24*time.Hour)
+			issuer.Now = func() time.Time { return testNow }
+			v := NewVerifier(key)
+			v.Now = func() time.Time { return testNow.Add(time.Minute) }
+
+			tokens, err := issuer.IssueTokens(testUser)
+			if err != nil {
+				t.Fatalf("IssueTokens() error = %v", err)
+			}
+			if tokens.TokenType != "Bearer" || tokens.ExpiresIn != 900 || !tokens.ExpiresAt.Equal(testNow.Add(15*time.Minute)) {
+				t.Errorf("tokens = %+v", tokens)
+			}
+
+			session, err := v.Session(tokens.AccessToken)
+			if err != nil {
+				t.Fatalf("Session() error = %v", err)
+			}
+			if session.UserID != testUser.ID || session.Email != testUser.Email || session.Role != testUser.Role ||
+				!session.IssuedAt.Equal(testNow) || !session.ExpiresAt.Equal(testNow.Add(15*time.Minute)) {
+				t.Errorf("Session() = %+v", session)
+			}
+
+			refresh, err := v.Verify(tokens.RefreshToken, types.TokenTypeRefresh)
+			if err != nil {
+				t.Fatalf("Verify(refresh) error = %v", err)
+			}
+			if refresh.TokenID == "" || refresh.ExpiresAt != testNow.Add(24*time.Hour).Unix() {
+				t.Errorf("refresh claims = %+v", refresh)
+			}
+			if _, err := v.Verify(tokens.RefreshToken, types.TokenTypeAccess); !errors.Is(err, types.ErrInvalidToken) {
+				t.Errorf("Verify(refresh as access) error = %v", err)
+			}
+		})
+	}
+}
+
+func TestPublicKeysCannotSign(t *testing.T) {
+	keys := testKeys(t)
+	public := []Key{
+		NewRS256PublicKey(keys[1].(*rsaKey).public),
+		NewEdDSAPublicKey(keys[2].(*ed25519Key).public),
+	}
+	for i, key := range public {
+		t.Run(string(key.Algorithm()), func(t *testing.T) {
+			if _, err := NewIssuer(key, time.Minute, time.Hour).IssueAccessToken(testUser); !errors.Is(err, ErrVerifyOnly) {
+				t.Errorf("IssueAccessToken() error = %v, want ErrVerifyOnly", err)
+			}
+
+			// The public half verifies what the private half signed
+			token := rawToken(t, keys[i+1], header{Algorithm: key.Algorithm()}, testClaims(types.TokenTypeAccess, testNow, time.Hour))
+			v := NewVerifier(key)
+			v.Now = func() time.Time { return testNow }
+			if _, err := v.Verify(token, types.TokenTypeAccess); err != nil {
+				t.Errorf("Verify() error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// Common transition reasons
+const (
+	ReasonTrialEnded       = "trial_ended"
+	ReasonPaymentSucceeded = "payment_succeeded"
+	ReasonPaymentFailed    = "payment_failed"
+	ReasonRetriesExhausted = "retries_exhausted"
+	ReasonSetupExpired     = "setup_expired"
+	ReasonPaused           = "paused"
+	ReasonResumed          = "resumed"
+	ReasonCanceled         = "canceled"
+)
+
+// Transition is a status change applied to a subscription
+type Transition struct {
+	SubscriptionID string                   `json:"subscription_id"`
+	From           types.SubscriptionStatus `json:"from"`
+	To             types.SubscriptionStatus `json:"to"`
+	Reason         string                   `json:"reason,omitempty"`
+	At             time.Time                `json:"at"`
+}
+
+// Listener is notified of every transition a Machine accepts
+type Listener func(ctx context.Context, transition Transition)
+
+// TransitionError reports a status change the machine does not allow.
+// It matches types.ErrInvalidTransition and types.ErrConflict with errors.Is.
+type TransitionError struct {
+	From types.SubscriptionStatus
+	To   types.SubscriptionStatus
+}
+
+// Error implements the error interface
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("lifecycle: cannot move subscription from %q to %q", e.From, e.To)
+}
+
+// APIError converts the error to an invalid_status_transition APIError
+func (e *TransitionError) APIError() *types.APIError {
+	return types.ErrInvalidTransition.WithDetails(fmt.Sprintf("%s -> %s", e.From, e.To))
+}
+
+// Is matches types.ErrInvalidTransition and the generic codes it refines
+func (e *TransitionError) Is(target error) bool {
+	return e.APIError().Is(target)
+}
+
+// stripeTransitions are the status changes Stripe performs on a subscription
+var stripeTransitions = map[types.SubscriptionStatus][]types.SubscriptionStatus{
+	types.SubscriptionStatusIncomplete: {
+		types.SubscriptionStatusActive,
+		types.SubscriptionStatusTrialing,
+		types.SubscriptionStatusIncompleteExpired,
+		types.SubscriptionStatusCanceled,
+	},
+	types.SubscriptionStatusTrialing: {
+		types.SubscriptionStatusActive,
+		types.SubscriptionStatusPastDue,
+		types.SubscriptionStatusUnpaid,
+		types.SubscriptionStatusPaused,
+		types.SubscriptionStatusCanceled,
+	},
+	types.SubscriptionStatusActive: {
+		types.SubscriptionStatusPastDue,
+		types.SubscriptionStatusCanceled,
+	},
+	types.SubscriptionStatusPastDue: {
+		types.SubscriptionStatusActive,
+		types.SubscriptionStatusUnpaid,
+		types.SubscriptionStatusCanceled,
+	},
+	types.SubscriptionStatusUnpaid: {
+		types.SubscriptionStatusActive,
+		types.SubscriptionStatusCanceled,
+	},
+	types.SubscriptionStatusPaused: {
+		types.SubscriptionStatusActive,
+		types.SubscriptionStatusCanceled,
+	},
+	types.SubscriptionStatusCanceled:          {},
+	types.SubscriptionStatusIncompleteExpired: {},
+}
+
+// Machine validates subscription status changes against a transition table
+// and notifies listeners of the ones it accepts.
+// Moving a subscription to the status it already has is always allowed and emits nothing.
+type Machine struct {
+	transitions map[types.SubscriptionStatus]map[types.SubscriptionStatus]bool
+
+	mu        sync.RWMutex
+	listeners []Listener
+
+	// Now returns the current time; defaults to time.Now
+	Now func() time.Time
+}
+
+// NewMachine creates a machine that follows the Stripe subscription lifecycle
+func NewMachine() *Machine {
+	m := &Machine{transitions: map[types.SubscriptionStatus]map[types.SubscriptionStatus]bool{}}
+	for from, targets := range stripeTransitions {
+		for _, to := range targets {
+			m.allow(from, to)
+		}
+	}
+	return m
+}
+
+// OnTransition registers a listener for accepted transitions.
+// Listeners run synchronously, in registration order.
+func (m *Machine) OnTransition(listener Listener) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.listeners = append(m.listeners, listener)
+}
+
+// Can reports whether a subscription may move from one status to another
+func (m *Machine) Can(from, to types.SubscriptionStatus) bool {
+	if !from.Valid() || !to.Valid() {
+		return false
+	}
+	return from == to || m.transitions[from][to]
+}
+
+// Allowed returns the statuses reachable from the given one, sorted by name
+func (m *Machine) Allowed(from types.SubscriptionStatus) []types.SubscriptionStatus {
+	allowed := make([]types.SubscriptionStatus, 0, len(m.transitions[from]))
+	for to := range m.transitions[from] {
+		allowed = append(allowed, to)
+	}
+	sort.Slice(allowed, func(i, j int) bool { return allowed[i] < allowed[j] })
+	return allowed
+}
+
+// Terminal reports whether no transition leaves the status
+func (m *Machine) Terminal(status types.SubscriptionStatus) bool {
+	return status.Valid() && len(m.transitions[status]) == 0
+}
+
+// Transition validates a status change and notifies listeners.
+// It returns nil without error when from and to are equal, and a *TransitionError when the change is not allowed.
+func (m *Machine) Transition(ctx context.Context, subscriptionID string, from, to types.SubscriptionStatus, reason string) (*Transition, error) {
+	if !m.Can(from, to) {
+		return nil, &TransitionError{From: from, To: to}
+	}
+	if from == to {
+		return nil, nil
+	}
+
+	transition := Transition{
+		SubscriptionID: subscriptionID,
+		From:           from,
+		To:             to,
+		Reason:         reason,
+		At:             m.now(),
+	}
+
+	m.mu.RLock()
+	listeners := m.listeners
+	m.mu.RUnlock()
+	for _, listener := range listeners {
+		listener(ctx, transition)
+	}
+	return &transition, nil
+}
+
+// ApplyUpdate validates the status change carried by req against the current subscription.
+// Requests without a status are always allowed.
+func (m *Machine) ApplyUpdate(ctx context.Context, current *types.SubscriptionResponse, req types.SubscriptionUpdateRequest, reason string) (*Transition, error) {
+	if req.Status == nil {
+		return nil, nil
+	}
+	return m.Transition(ctx, current.ID, current.Status, *req.Status, reason)
+}
+
+func (m *Machine) allow(from, to types.SubscriptionStatus) {
+	if m.transitions[from] == nil {
+		m.transitions[from] = map[types.SubscriptionStatus]bool{}
+	}
+	m.transitions[from][to] = true
+}
+
+func (m *Machine) now() time.Time {
+	if m.Now != nil {
+		return m.Now()
+	}
+	return time.Now()
+}
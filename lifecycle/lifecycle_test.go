@@ -0,0 +1,192 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+const (
+	incomplete        = types.SubscriptionStatusIncomplete
+	incompleteExpired = types.SubscriptionStatusIncompleteExpired
+	trialing          = types.SubscriptionStatusTrialing
+	active            = types.SubscriptionStatusActive
+	pastDue           = types.SubscriptionStatusPastDue
+	unpaid            = types.SubscriptionStatusUnpaid
+	paused            = types.SubscriptionStatusPaused
+	canceled          = types.SubscriptionStatusCanceled
+)
+
+var subscriptionStatuses = []types.SubscriptionStatus{
+	incomplete, incompleteExpired, trialing, active, pastDue, unpaid, paused, canceled,
+}
+
+func newTestMachine() (*Machine, time.Time) {
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	m := NewMachine()
+	m.Now = func() time.Time { return now }
+	return m, now
+}
+
+func TestSubscriptionTransitionTable(t *testing.T) {
+	allowed := map[[2]types.SubscriptionStatus]bool{
+		{incomplete, active}: true, {incomplete, trialing}: true, {incomplete, incompleteExpired}: true, {incomplete, canceled}: true,
+		{trialing, active}: true, {trialing, pastDue}: true, {trialing, unpaid}: true, {trialing, paused}: true, {trialing, canceled}: true,
+		{active, pastDue}: true, {active, canceled}: true,
+		{pastDue, active}: true, {pastDue, unpaid}: true, {pastDue, canceled}: true,
+		{unpaid, active}: true, {unpaid, canceled}: true,
+		{paused, active}: true, {paused, canceled}: true,
+	}
+
+	ctx := context.Background()
+	for _, from := range subscriptionStatuses {
+		for _, to := range subscriptionStatuses {
+			t.Run(string(from)+"->"+string(to), func(t *testing.T) {
+				m, now := newTestMachine()
+				want := from == to || allowed[[2]types.SubscriptionStatus{from, to}]
+				if got := m.Can(from, to); got != want {
+					t.Errorf("Can() = %v, want %v", got, want)
+				}
+
+				transition, err := m.Transition(ctx, "sub_1", from, to, ReasonPaymentSucceeded)
+				switch {
+				case !want:
+					var transitionErr *TransitionError
+					if !errors.As(err, &transitionErr) || transitionErr.From != from || transitionErr.To != to {
+						t.Errorf("Transition() error = %v, want *TransitionError", err)
+					}
+					if transition != nil {
+						t.Errorf("Transition() = %+v for a rejected change", transition)
+					}
+				case from == to:
+					if transition != nil || err != nil {
+						t.Errorf("Transition() = %+v, %v, want nil, nil", transition, err)
+					}
+				default:
+					if err != nil {
+						t.Fatalf("Transition() error = %v", err)
+					}
+					want := Transition{SubscriptionID: "sub_1", From: from, To: to, Reason: ReasonPaymentSucceeded, At: now}
+					if *transition != want {
+						t.Errorf("Transition() = %+v, want %+v", *transition, want)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestSubscriptionTerminalStatuses(t *testing.T) {
+	m, _ := newTestMachine()
+	for _, status := range subscriptionStatuses {
+		want := status == canceled || status == incompleteExpired
+		if got := m.Terminal(status); got != want {
+			t.Errorf("Terminal(%s) = %v, want %v", status, got, want)
+		}
+		if got := len(m.Allowed(status)) == 0; got != want {
+			t.Errorf("Allowed(%s) = %v", status, m.Allowed(status))
+		}
+	}
+
+	want := []types.SubscriptionStatus{active, canceled, pastDue, paused, unpaid}
+	got := m.Allowed(trialing)
+	if len(got) != len(want) {
+		t.Fatalf("Allowed(trialing) = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Allowed(trialing) = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestSubscriptionUnknownStatuses(t *testing.T) {
+	m, _ := newTestMachine()
+	for _, tt := range [][2]types.SubscriptionStatus{{"lapsed", active}, {active, "lapsed"}, {"lapsed", "lapsed"}, {"", active}} {
+		if m.Can(tt[0], tt[1]) {
+			t.Errorf("Can(%q, %q) = true", tt[0], tt[1])
+		}
+		if _, err := m.Transition(context.Background(), "sub_1", tt[0], tt[1], ""); !errors.Is(err, types.ErrInvalidTransition) {
+			t.Errorf("Transition(%q, %q) error = %v", tt[0], tt[1], err)
+		}
+	}
+	if m.Terminal("lapsed") {
+		t.Error("Terminal(unknown) = true")
+	}
+}
+
+func TestSubscriptionTransitionError(t *testing.T) {
+	m, _ := newTestMachine()
+	_, err := m.Transition(context.Background(), "sub_1", canceled, active, ReasonResumed)
+
+	if !errors.Is(err, types.ErrInvalidTransition) || !errors.Is(err, types.ErrConflict) {
+		t.Errorf("error %v does not match ErrInvalidTransition and ErrConflict", err)
+	}
+	if errors.Is(err, types.ErrNotFound) {
+		t.Errorf("error %v matches ErrNotFound", err)
+	}
+
+	apiErr := types.ToAPIError(err)
+	if apiErr.Code != types.ErrorCodeInvalidTransition || apiErr.HTTPStatus() != http.StatusConflict || apiErr.Details != "canceled -> active" {
+		t.Errorf("ToAPIError() = %+v", apiErr)
+	}
+}
+
+func TestSubscriptionListeners(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestMachine()
+
+	var calls []string
+	m.OnTransition(func(ctx context.Context, tr Transition) { calls = append(calls, "first:"+string(tr.To)) })
+	m.OnTransition(func(ctx context.Context, tr Transition) { calls = append(calls, "second:"+string(tr.To)) })
+
+	m.Transition(ctx, "sub_1", trialing, active, ReasonTrialEnded)
+	m.Transition(ctx, "sub_1", active, active, "")
+	m.Transition(ctx, "sub_1", canceled, active, "")
+
+	if len(calls) != 2 || calls[0] != "first:active" || calls[1] != "second:active" {
+		t.Errorf("listener calls = %v, want one notification per listener for the accepted change", calls)
+	}
+}
+
+func TestSubscriptionApplyUpdate(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestMachine()
+	current := &types.SubscriptionResponse{ID: "sub_1", Status: pastDue}
+	status := func(s types.SubscriptionStatus) *types.SubscriptionStatus { return &s }
+	plan := "pro"
+
+	tests := []struct {
+		name    string
+		req     types.SubscriptionUpdateRequest
+		wantTo  types.SubscriptionStatus
+		wantErr bool
+	}{
+		{"no status", types.SubscriptionUpdateRequest{Plan: &plan}, "", false},
+		{"same status", types.SubscriptionUpdateRequest{Status: status(pastDue)}, "", false},
+		{"allowed", types.SubscriptionUpdateRequest{Status: status(active)}, active, false},
+		{"not allowed", types.SubscriptionUpdateRequest{Status: status(trialing)}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transition, err := m.ApplyUpdate(ctx, current, tt.req, ReasonPaymentSucceeded)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ApplyUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantTo == "" {
+				if transition != nil {
+					t.Errorf("ApplyUpdate() = %+v, want nil", transition)
+				}
+				return
+			}
+			if transition == nil || transition.SubscriptionID != "sub_1" || transition.From != pastDue || transition.To != tt.wantTo {
+				t.Errorf("ApplyUpdate() = %+v", transition)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// Phone number transition reasons
+const (
+	ReasonProvisioned       = "provisioned"
+	ReasonDeactivated       = "deactivated"
+	ReasonSuspended         = "suspended"
+	ReasonReactivated       = "reactivated"
+	ReasonReleaseRequested  = "release_requested"
+	ReasonReclaimed         = "reclaimed"
+	ReasonQuarantineExpired = "quarantine_expired"
+)
+
+// PhoneTransitionError reports a phone number status change the machine does not allow.
+// It matches types.ErrInvalidTransition and types.ErrConflict with errors.Is.
+type PhoneTransitionError struct {
+	From types.PhoneNumberStatus
+	To   types.PhoneNumberStatus
+	// Detail explains refusals that depend on more than the two statuses
+	Detail string
+}
+
+// Error implements the error interface
+func (e *PhoneTransitionError) Error() string {
+	msg := fmt.Sprintf("lifecycle: cannot move phone number from %q to %q", e.From, e.To)
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	return msg
+}
+
+// APIError converts the error to an invalid_status_transition APIError
+func (e *PhoneTransitionError) APIError() *types.APIError {
+	details := fmt.Sprintf("phone number %s -> %s", e.From, e.To)
+	if e.Detail != "" {
+		details += ": " + e.Detail
+	}
+	return types.ErrInvalidTransition.WithDetails(details)
+}
+
+// Is matches types.ErrInvalidTransition and the generic codes it refines
+func (e *PhoneTransitionError) Is(target error) bool {
+	return e.APIError().Is(target)
+}
+
+// phoneTransitions are the status changes a phone number may go through.
+// Released numbers are back with the provider, so nothing leaves released.
+var phoneTransitions = map[types.PhoneNumberStatus][]types.PhoneNumberStatus{
+	types.PhoneNumberStatusPurchased: {
+		types.PhoneNumberStatusActive,
+		types.PhoneNumberStatusReleased,
+	},
+	types.PhoneNumberStatusActive: {
+		types.PhoneNumberStatusInactive,
+		types.PhoneNumberStatusSuspended,
+		types.PhoneNumberStatusQuarantined,
+		types.PhoneNumberStatusReleased,
+	},
+	types.PhoneNumberStatusInactive: {
+		types.PhoneNumberStatusActive,
+		types.PhoneNumberStatusSuspended,
+		types.PhoneNumberStatusQuarantined,
+		types.PhoneNumberStatusReleased,
+	},
+	types.PhoneNumberStatusSuspended: {
+		types.PhoneNumberStatusActive,
+		types.PhoneNumberStatusQuarantined,
+		types.PhoneNumberStatusReleased,
+	},
+	types.PhoneNumberStatusQuarantined: {
+		types.PhoneNumberStatusActive,
+		types.PhoneNumberStatusReleased,
+	},
+	types.PhoneNumberStatusReleased: {},
+}
+
+// PhoneHistory persists phone number status changes
+type PhoneHistory interface {
+	// Append records a status change
+	Append(ctx context.Context, change types.PhoneNumberStatusChange) error
+	// List returns the changes of a phone number, oldest first
+	List(ctx context.Context, phoneNumberID uuid.UUID) ([]types.PhoneNumberStatusChange, error)
+}
+
+// MemoryPhoneHistory is an in-memory PhoneHistory for tests and single-instance services
+type MemoryPhoneHistory struct {
+	mu      sync.Mutex
+	changes map[uuid.UUID][]types.PhoneNumberStatusChange
+}
+
+var _ PhoneHistory = (*MemoryPhoneHistory)(nil)
+
+// NewMemoryPhoneHistory creates an empty in-memory history
+func NewMemoryPhoneHistory() *MemoryPhoneHistory {
+	return &MemoryPhoneHistory{changes: map[uuid.UUID][]types.PhoneNumberStatusChange{}}
+}
+
+// Append records a status change
+func (h *MemoryPhoneHistory) Append(ctx context.Context, change types.PhoneNumberStatusChange) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.changes[change.PhoneNumberID] = append(h.changes[change.PhoneNumberID], change)
+	return nil
+}
+
+// List returns a copy of the changes of a phone number, oldest first
+func (h *MemoryPhoneHistory) List(ctx context.Context, phoneNumberID uuid.UUID) ([]types.PhoneNumberStatusChange, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return append([]types.PhoneNumberStatusChange(nil), h.changes[phoneNumberID]...), nil
+}
+
+// PhoneMachine applies phone number status changes, enforcing the transition table
+// and recording every accepted change in a PhoneHistory.
+// Moving a number to the status it already has is always allowed and records nothing.
+type PhoneMachine struct {
+	transitions map[types.PhoneNumberStatus]map[types.PhoneNumberStatus]bool
+	history     PhoneHistory
+
+	// Quarantine is how long a released number stays reclaimable before it goes back
+	// to the provider. Zero releases numbers immediately.
+	Quarantine time.Duration
+
+	// Now returns the current time; defaults to time.Now
+	Now func() time.Time
+}
+
+// NewPhoneMachine creates a machine recording changes in history, with the given release quarantine
+func NewPhoneMachine(history PhoneHistory, quarantine time.Duration) *PhoneMachine {
+	m := &PhoneMachine{
+		transitions: map[types.PhoneNumberStatus]map[types.PhoneNumberStatus]bool{},
+		history:     history,
+		Quarantine:  quarantine,
+	}
+	for from, targets := range phoneTransitions {
+		for _, to := range targets {
+			if m.transitions[from] == nil {
+				m.transitions[from] = map[types.PhoneNumberStatus]bool{}
+			}
+			m.transitions[from][to] = true
+		}
+	}
+	return m
+}
+
+// Can reports whether a phone number may move from one status to another
+func (m *PhoneMachine) Can(from, to types.PhoneNumberStatus) bool {
+	if !from.Valid() || !to.Valid() {
+		return false
+	}
+	return from == to || m.transitions[from][to]
+}
+
+// Allowed returns the statuses reachable from the given one, sorted by name
+func (m *PhoneMachine) Allowed(from types.PhoneNumberStatus) []types.PhoneNumberStatus {
+	allowed := make([]types.PhoneNumberStatus, 0, len(m.transitions[from]))
+	for to := range m.transitions[from] {
+		allowed = append(allowed, to)
+	}
+	sort.Slice(allowed, func(i, j int) bool { return allowed[i] < allowed[j] })
+	return allowed
+}
+
+// Terminal reports whether no transition leaves the status
+func (m *PhoneMachine) Terminal(status types.PhoneNumberStatus) bool {
+	return status.Valid() && len(m.transitions[status]) == 0
+}
+
+// Transition moves number to the status, updating Status, UpdatedAt and QuarantineEndsAt
+// and recording the change. It returns nil without error when the status is unchanged,
+// and a *PhoneTransitionError when the change is not allowed.
+// Moving a number into quarantine starts the machine's Quarantine period.
+func (m *PhoneMachine) Transition(ctx context.Context, number *types.PhoneNumber, to types.PhoneNumberStatus, reason string) (*types.PhoneNumberStatusChange, error) {
+	from := number.Status
+	if !m.Can(from, to) {
+		return nil, &PhoneTransitionError{From: from, To: to}
+	}
+	if from == to {
+		return nil, nil
+	}
+
+	now := m.now()
+	change := types.PhoneNumberStatusChange{
+		PhoneNumberID: number.ID,
+		From:          from,
+		To:            to,
+		Reason:        reason,
+		At:            now,
+	}
+	if err := m.history.Append(ctx, change); err != nil {
+		return nil, fmt.Errorf("lifecycle: record phone number transition: %w", err)
+	}
+
+	number.Status = to
+	number.UpdatedAt = now
+	number.QuarantineEndsAt = nil
+	if to == types.PhoneNumberStatusQuarantined {
+		endsAt := now.Add(m.Quarantine)
+		number.QuarantineEndsAt = &endsAt
+	}
+	return &change, nil
+}
+
+// Release quarantines the number when the machine has a Quarantine period and releases it otherwise.
+// The caller returns the number to the provider once the returned change moves it to released.
+func (m *PhoneMachine) Release(ctx context.Context, number *types.PhoneNumber, reason string) (*types.PhoneNumberStatusChange, error) {
+	if reason == "" {
+		reason = ReasonReleaseRequested
+	}
+	if m.Quarantine > 0 && number.Status != types.PhoneNumberStatusQuarantined {
+		return m.Transition(ctx, number, types.PhoneNumberStatusQuarantined, reason)
+	}
+	return m.Transition(ctx, number, types.PhoneNumberStatusReleased, reason)
+}
+
+// Reclaim reactivates a quarantined number.
+// It fails with a *PhoneTransitionError once the quarantine has ended.
+func (m *PhoneMachine) Reclaim(ctx context.Context, number *types.PhoneNumber) (*types.PhoneNumberStatusChange, error) {
+	if number.Status != types.PhoneNumberStatusQuarantined {
+		return nil, &PhoneTransitionError{From: number.Status, To: types.PhoneNumberStatusActive, Detail: "number is not quarantined"}
+	}
+	if m.QuarantineEnded(number) {
+		return nil, &PhoneTransitionError{From: number.Status, To: types.PhoneNumberStatusActive, Detail: "quarantine has ended"}
+	}
+	return m.Transition(ctx, number, types.PhoneNumberStatusActive, ReasonReclaimed)
+}
+
+// QuarantineEnded reports whether a quarantined number is due to go back to the provider
+func (m *PhoneMachine) QuarantineEnded(number *types.PhoneNumber) bool {
+	if number.Status != types.PhoneNumberStatusQuarantined {
+		return false
+	}
+	return number.QuarantineEndsAt == nil || !m.now().Before(*number.QuarantineEndsAt)
+}
+
+// Expire releases the number if its quarantine has ended, and returns nil otherwise.
+// Services call it periodically on quarantined numbers and return released ones to the provider.
+func (m *PhoneMachine) Expire(ctx context.Context, number *types.PhoneNumber) (*types.PhoneNumberStatusChange, error) {
+	if !m.QuarantineEnded(number) {
+		return nil, nil
+	}
+	return m.Transition(ctx, number, types.PhoneNumberStatusReleased, ReasonQuarantineExpired)
+}
+
+// History returns the recorded changes of a phone number, oldest first
+func (m *PhoneMachine) History(ctx context.Context, phoneNumberID uuid.UUID) ([]types.PhoneNumberStatusChange, error) {
+	return m.history.List(ctx, phoneNumberID)
+}
+
+func (m *PhoneMachine) now() time.Time {
+	if m.Now != nil {
+		return m.Now()
+	}
+	return time.Now()
+}
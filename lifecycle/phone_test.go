@@ -0,0 +1,215 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+var phoneStatuses = []types.PhoneNumberStatus{
+	types.PhoneNumberStatusPurchased,
+	types.PhoneNumberStatusActive,
+	types.PhoneNumberStatusInactive,
+	types.PhoneNumberStatusSuspended,
+	types.PhoneNumberStatusQuarantined,
+	types.PhoneNumberStatusReleased,
+}
+
+func newTestPhoneMachine(quarantine time.Duration) (*PhoneMachine, *time.Time) {
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	m := NewPhoneMachine(NewMemoryPhoneHistory(), quarantine)
+	m.Now = func() time.Time { return now }
+	return m, &now
+}
+
+func TestPhoneTransitionTable(t *testing.T) {
+	const (
+		purchased   = types.PhoneNumberStatusPurchased
+		active      = types.PhoneNumberStatusActive
+		inactive    = types.PhoneNumberStatusInactive
+		suspended   = types.PhoneNumberStatusSuspended
+		quarantined = types.PhoneNumberStatusQuarantined
+		released    = types.PhoneNumberStatusReleased
+	)
+	allowed := map[[2]types.PhoneNumberStatus]bool{
+		{purchased, active}: true, {purchased, released}: true,
+		{active, inactive}: true, {active, suspended}: true, {active, quarantined}: true, {active, released}: true,
+		{inactive, active}: true, {inactive, suspended}: true, {inactive, quarantined}: true, {inactive, released}: true,
+		{suspended, active}: true, {suspended, quarantined}: true, {suspended, released}: true,
+		{quarantined, active}: true, {quarantined, released}: true,
+	}
+
+	m, _ := newTestPhoneMachine(0)
+	for _, from := range phoneStatuses {
+		for _, to := range phoneStatuses {
+			want := from == to || allowed[[2]types.PhoneNumberStatus{from, to}]
+			if got := m.Can(from, to); got != want {
+				t.Errorf("Can(%s, %s) = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+
+	if m.Can("lost", types.PhoneNumberStatusActive) || m.Can(types.PhoneNumberStatusActive, "lost") {
+		t.Error("Can() accepted an unknown status")
+	}
+	want := []types.PhoneNumberStatus{inactive, quarantined, released, suspended}
+	if got := m.Allowed(active); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] || got[3] != want[3] {
+		t.Errorf("Allowed(active) = %v, want %v", got, want)
+	}
+}
+
+func TestPhoneReleasedIsTerminal(t *testing.T) {
+	m, _ := newTestPhoneMachine(0)
+	for _, status := range phoneStatuses {
+		if got := m.Terminal(status); got != (status == types.PhoneNumberStatusReleased) {
+			t.Errorf("Terminal(%s) = %v", status, got)
+		}
+	}
+	if len(m.Allowed(types.PhoneNumberStatusReleased)) != 0 {
+		t.Error("Allowed(released) is not empty")
+	}
+
+	number := &types.PhoneNumber{ID: uuid.New(), Status: types.PhoneNumberStatusReleased}
+	_, err := m.Transition(context.Background(), number, types.PhoneNumberStatusActive, ReasonReactivated)
+
+	var transitionErr *PhoneTransitionError
+	if !errors.As(err, &transitionErr) || transitionErr.From != types.PhoneNumberStatusReleased {
+		t.Fatalf("Transition(released -> active) error = %v, want *PhoneTransitionError", err)
+	}
+	if !errors.Is(err, types.ErrInvalidTransition) || !errors.Is(err, types.ErrConflict) {
+		t.Errorf("error %v does not match ErrInvalidTransition and ErrConflict", err)
+	}
+	if apiErr := transitionErr.APIError(); apiErr.Code != types.ErrorCodeInvalidTransition || apiErr.HTTPStatus() != http.StatusConflict {
+		t.Errorf("APIError() = %+v", apiErr)
+	}
+	if number.Status != types.PhoneNumberStatusReleased {
+		t.Errorf("status = %s after a rejected transition", number.Status)
+	}
+}
+
+func TestPhoneTransitionRecordsHistory(t *testing.T) {
+	ctx := context.Background()
+	m, now := newTestPhoneMachine(0)
+	number := &types.PhoneNumber{ID: uuid.New(), Status: types.PhoneNumberStatusPurchased}
+
+	change, err := m.Transition(ctx, number, types.PhoneNumberStatusActive, ReasonProvisioned)
+	if err != nil || change == nil {
+		t.Fatalf("Transition() = %v, %v", change, err)
+	}
+	if number.Status != types.PhoneNumberStatusActive || !number.UpdatedAt.Equal(*now) {
+		t.Errorf("number = %+v", number)
+	}
+
+	// Unchanged status is a no-op
+	if change, err := m.Transition(ctx, number, types.PhoneNumberStatusActive, ReasonReactivated); change != nil || err != nil {
+		t.Errorf("Transition(active -> active) = %v, %v, want nil, nil", change, err)
+	}
+
+	*now = now.Add(time.Hour)
+	if _, err := m.Transition(ctx, number, types.PhoneNumberStatusSuspended, ReasonSuspended); err != nil {
+		t.Fatalf("Transition(suspended) error = %v", err)
+	}
+
+	history, err := m.History(ctx, number.ID)
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("History() has %d changes, want 2", len(history))
+	}
+	if h := history[1]; h.From != types.PhoneNumberStatusActive || h.To != types.PhoneNumberStatusSuspended || h.Reason != ReasonSuspended || !h.At.Equal(*now) {
+		t.Errorf("History()[1] = %+v", h)
+	}
+}
+
+func TestPhoneQuarantine(t *testing.T) {
+	const quarantine = 72 * time.Hour
+	ctx := context.Background()
+
+	release := func(t *testing.T) (*PhoneMachine, *time.Time, *types.PhoneNumber) {
+		t.Helper()
+		m, now := newTestPhoneMachine(quarantine)
+		number := &types.PhoneNumber{ID: uuid.New(), Status: types.PhoneNumberStatusActive}
+		change, err := m.Release(ctx, number, "")
+		if err != nil {
+			t.Fatalf("Release() error = %v", err)
+		}
+		if change.To != types.PhoneNumberStatusQuarantined || change.Reason != ReasonReleaseRequested {
+			t.Fatalf("Release() = %+v, want quarantined", change)
+		}
+		if number.QuarantineEndsAt == nil || !number.QuarantineEndsAt.Equal(now.Add(quarantine)) {
+			t.Fatalf("QuarantineEndsAt = %v, want %s", number.QuarantineEndsAt, now.Add(quarantine))
+		}
+		return m, now, number
+	}
+
+	t.Run("reclaim before the end", func(t *testing.T) {
+		m, now, number := release(t)
+		*now = number.QuarantineEndsAt.Add(-time.Second)
+		if _, err := m.Reclaim(ctx, number); err != nil {
+			t.Fatalf("Reclaim() error = %v", err)
+		}
+		if number.Status != types.PhoneNumberStatusActive || number.QuarantineEndsAt != nil {
+			t.Errorf("number = %s, quarantine ends %v, want active without quarantine", number.Status, number.QuarantineEndsAt)
+		}
+	})
+
+	t.Run("reclaim at the end", func(t *testing.T) {
+		m, now, number := release(t)
+		*now = *number.QuarantineEndsAt
+		if _, err := m.Reclaim(ctx, number); !errors.Is(err, types.ErrInvalidTransition) {
+			t.Errorf("Reclaim() error = %v, want invalid transition", err)
+		}
+	})
+
+	t.Run("reclaim a number that is not quarantined", func(t *testing.T) {
+		m, _ := newTestPhoneMachine(quarantine)
+		number := &types.PhoneNumber{Status: types.PhoneNumberStatusSuspended}
+		if _, err := m.Reclaim(ctx, number); !errors.Is(err, types.ErrInvalidTransition) {
+			t.Errorf("Reclaim() error = %v, want invalid transition", err)
+		}
+	})
+
+	t.Run("expire", func(t *testing.T) {
+		m, now, number := release(t)
+		*now = number.QuarantineEndsAt.Add(-time.Second)
+		if change, err := m.Expire(ctx, number); change != nil || err != nil {
+			t.Fatalf("Expire() before the end = %v, %v, want nil, nil", change, err)
+		}
+		if m.QuarantineEnded(number) {
+			t.Error("QuarantineEnded() = true before the end")
+		}
+
+		*now = *number.QuarantineEndsAt
+		change, err := m.Expire(ctx, number)
+		if err != nil || change == nil || change.Reason != ReasonQuarantineExpired {
+			t.Fatalf("Expire() at the end = %+v, %v", change, err)
+		}
+		if number.Status != types.PhoneNumberStatusReleased || number.QuarantineEndsAt != nil {
+			t.Errorf("number = %s, quarantine ends %v, want released", number.Status, number.QuarantineEndsAt)
+		}
+	})
+
+	t.Run("release again skips the wait", func(t *testing.T) {
+		m, _, number := release(t)
+		if _, err := m.Release(ctx, number, ""); err != nil || number.Status != types.PhoneNumberStatusReleased {
+			t.Errorf("Release() = %s, %v, want released", number.Status, err)
+		}
+	})
+
+	t.Run("no quarantine releases immediately", func(t *testing.T) {
+		m, _ := newTestPhoneMachine(0)
+		number := &types.PhoneNumber{Status: types.PhoneNumberStatusActive}
+		if _, err := m.Release(ctx, number, ""); err != nil || number.Status != types.PhoneNumberStatusReleased {
+			t.Errorf("Release() = %s, %v, want released", number.Status, err)
+		}
+		if m.QuarantineEnded(number) {
+			t.Error("QuarantineEnded() = true for a released number")
+		}
+	})
+}
@@ -0,0 +1,157 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+var testNow = time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestCodec() (*Codec, *time.Time) {
+	now := testNow
+	c := NewCodec([]byte("cursor secret"))
+	c.Now = func() time.Time { return now }
+	return c, &now
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	c, _ := newTestCodec()
+	scope := Fingerprint("created_at,id", "admin")
+	want := Cursor{Key: []string{TimeKey(testNow), "u1"}, Direction: Prev, Scope: scope}
+
+	encoded, err := c.Encode(want)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	got, err := c.Decode(encoded, scope)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if got.String() != want.String() || got.Scope != scope || got.ExpiresAt != testNow.Add(DefaultTTL).Unix() {
+		t.Errorf("Decode() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCursorRejectsTampering(t *testing.T) {
+	c, _ := newTestCodec()
+	scope := Fingerprint("id")
+	encoded, err := c.Encode(Cursor{Key: []string{"42"}, Direction: Next, Scope: scope})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	raw, _ := base64.RawURLEncoding.DecodeString(encoded)
+
+	edited := func(from, to string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(strings.Replace(string(raw), from, to, 1)))
+	}
+	flipped := append([]byte(nil), raw...)
+	flipped[len(flipped)-1] ^= 1
+	other := NewCodec([]byte("another secret"))
+	otherEncoded, _ := other.Encode(Cursor{Key: []string{"42"}, Direction: Next, Scope: scope})
+
+	tests := []struct {
+		name    string
+		encoded string
+	}{
+		{"empty", ""},
+		{"not base64", "***"},
+		{"too short", base64.RawURLEncoding.EncodeToString(raw[:signatureBytes])},
+		{"edited key", edited(`"42"`, `"43"`)},
+		{"edited direction", edited(`"next"`, `"prev"`)},
+		{"edited expiry", edited(`"e":`, `"e":9`)},
+		{"flipped signature bit", base64.RawURLEncoding.EncodeToString(flipped)},
+		{"signed with another secret", otherEncoded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := c.Decode(tt.encoded, scope); !errors.Is(err, ErrInvalidCursor) || !errors.Is(err, types.ErrValidation) {
+				t.Errorf("Decode() error = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
+
+func TestCursorExpiry(t *testing.T) {
+	c, now := newTestCodec()
+	c.TTL = time.Hour
+	encoded, err := c.Encode(Cursor{Key: []string{"1"}, Direction: Next})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	*now = testNow.Add(time.Hour - time.Second)
+	if _, err := c.Decode(encoded, ""); err != nil {
+		t.Errorf("Decode() before expiry error = %v", err)
+	}
+	*now = testNow.Add(time.Hour)
+	if _, err := c.Decode(encoded, ""); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Decode() at expiry error = %v, want ErrInvalidCursor", err)
+	}
+
+	// Encode ignores a caller-supplied expiry, and a zero TTL never expires
+	c.TTL = 0
+	*now = testNow
+	encoded, err = c.Encode(Cursor{Key: []string{"1"}, Direction: Next, ExpiresAt: testNow.Unix()})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	*now = testNow.AddDate(1, 0, 0)
+	if cursor, err := c.Decode(encoded, ""); err != nil || cursor.ExpiresAt != 0 {
+		t.Errorf("Decode() without TTL = %+v, %v", cursor, err)
+	}
+}
+
+func TestCursorRejectsOtherQueries(t *testing.T) {
+	c, _ := newTestCodec()
+	issued := Fingerprint("created_at,id", "admin")
+	encoded, err := c.Encode(Cursor{Key: []string{"1"}, Direction: Next, Scope: issued})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		scope string
+	}{
+		{"other filter", Fingerprint("created_at,id", "member")},
+		{"other sort", Fingerprint("email,id", "admin")},
+		{"filter dropped", Fingerprint("created_at,id")},
+		{"parts joined differently", Fingerprint("created_at,idadmin")},
+		{"unscoped", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.scope == issued {
+				t.Fatalf("Fingerprint collides with the issuing query")
+			}
+			if _, err := c.Decode(encoded, tt.scope); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("Decode() error = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+	if Fingerprint("a", "bc") == Fingerprint("ab", "c") {
+		t.Error("Fingerprint() does not separate its parts")
+	}
+}
+
+func TestSortKeys(t *testing.T) {
+	at := time.Date(2026, 5, 1, 12, 0, 0, 123456789, time.FixedZone("CEST", 2*60*60))
+	got, err := ParseTimeKey(TimeKey(at))
+	if err != nil || !got.Equal(at) {
+		t.Errorf("ParseTimeKey(TimeKey()) = %s, %v, want %s", got, err, at)
+	}
+	if n, err := ParseIntKey(IntKey(-42)); err != nil || n != -42 {
+		t.Errorf("ParseIntKey(IntKey(-42)) = %d, %v", n, err)
+	}
+	if _, err := ParseTimeKey("yesterday"); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("ParseTimeKey(invalid) error = %v", err)
+	}
+	if _, err := ParseIntKey("4x"); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("ParseIntKey(invalid) error = %v", err)
+	}
+}
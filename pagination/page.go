@@ -0,0 +1,87 @@
+package pagination
+
+import (
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// Request is a decoded cursor-pagination request
+type Request struct {
+	// After is the sort key to page from; nil for the first page
+	After []string
+	// Direction is Next for forward paging and Prev for backward paging
+	Direction Direction
+	// Limit is the normalized page size
+	Limit int
+	// Scope is the query Fingerprint that cursors for this request are bound to
+	Scope string
+}
+
+// Fetch returns how many rows a store should load: one extra to detect further pages
+func (r Request) Fetch() int {
+	return r.Limit + 1
+}
+
+// ParseRequest decodes an optional cursor issued for scope, a Fingerprint of the query,
+// and normalizes the limit with types.NormalizeLimit
+func (c *Codec) ParseRequest(scope, cursor string, limit int) (Request, error) {
+	req := Request{Direction: Next, Limit: types.NormalizeLimit(limit), Scope: scope}
+	if cursor == "" {
+		return req, nil
+	}
+
+	decoded, err := c.Decode(cursor, scope)
+	if err != nil {
+		return req, err
+	}
+	req.After = decoded.Key
+	req.Direction = decoded.Direction
+	return req, nil
+}
+
+// NewPage builds a page from rows loaded for req.
+// For Next requests rows must be in ascending sort order after req.After;
+// for Prev requests they must be in descending order before it.
+// Up to req.Fetch() rows may be passed; key returns the sort key of a row.
+func NewPage[T any](c *Codec, req Request, rows []T, key func(T) []string) (types.Page[T], error) {
+	hasMore := len(rows) > req.Limit
+	if hasMore {
+		rows = rows[:req.Limit]
+	}
+	if req.Direction == Prev {
+		rows = reversed(rows)
+	}
+
+	page := types.Page[T]{Items: rows, Limit: req.Limit, HasMore: hasMore}
+	if len(rows) == 0 {
+		return page, nil
+	}
+
+	// Forward pages continue forward while rows remain and can always step back
+	// once a cursor was used; backward pages mirror that.
+	moreNext := hasMore
+	morePrev := req.After != nil
+	if req.Direction == Prev {
+		moreNext, morePrev = req.After != nil, hasMore
+	}
+
+	var err error
+	if moreNext {
+		if page.NextCursor, err = c.Encode(Cursor{Key: key(rows[len(rows)-1]), Direction: Next, Scope: req.Scope}); err != nil {
+			return page, err
+		}
+	}
+	if morePrev {
+		if page.PrevCursor, err = c.Encode(Cursor{Key: key(rows[0]), Direction: Prev, Scope: req.Scope}); err != nil {
+			return page, err
+		}
+	}
+	return page, nil
+}
+
+func reversed[T any](rows []T) []T {
+	out := make([]T, len(rows))
+	for i, row := range rows {
+		out[len(rows)-1-i] = row
+	}
+	return out
+}
@@ -0,0 +1,125 @@
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// listInts pages through rows the way a store would for req
+func listInts(t *testing.T, c *Codec, rows []int, req Request) types.Page[int] {
+	t.Helper()
+	var loaded []int
+	if req.Direction == Next {
+		for _, row := range rows {
+			if (req.After == nil || row > mustInt(t, req.After[0])) && len(loaded) < req.Fetch() {
+				loaded = append(loaded, row)
+			}
+		}
+	} else {
+		for i := len(rows) - 1; i >= 0; i-- {
+			if row := rows[i]; row < mustInt(t, req.After[0]) && len(loaded) < req.Fetch() {
+				loaded = append(loaded, row)
+			}
+		}
+	}
+	page, err := NewPage(c, req, loaded, func(n int) []string { return []string{IntKey(int64(n))} })
+	if err != nil {
+		t.Fatalf("NewPage() error = %v", err)
+	}
+	return page
+}
+
+func mustInt(t *testing.T, key string) int {
+	t.Helper()
+	n, err := ParseIntKey(key)
+	if err != nil {
+		t.Fatalf("ParseIntKey(%q) error = %v", key, err)
+	}
+	return int(n)
+}
+
+func TestPageWalk(t *testing.T) {
+	c, _ := newTestCodec()
+	rows := []int{1, 2, 3, 4, 5, 6, 7}
+	scope := Fingerprint("id")
+
+	steps := []struct {
+		follow   string
+		want     string
+		hasMore  bool
+		wantNext bool
+		wantPrev bool
+	}{
+		{"", "[1 2 3]", true, true, false},
+		{"next", "[4 5 6]", true, true, true},
+		{"next", "[7]", false, false, true},
+		{"prev", "[4 5 6]", true, true, true},
+		{"prev", "[1 2 3]", false, true, false},
+	}
+	var page types.Page[int]
+	for i, step := range steps {
+		cursor := ""
+		switch step.follow {
+		case "next":
+			cursor = page.NextCursor
+		case "prev":
+			cursor = page.PrevCursor
+		}
+		req, err := c.ParseRequest(scope, cursor, 3)
+		if err != nil {
+			t.Fatalf("step %d: ParseRequest() error = %v", i, err)
+		}
+		page = listInts(t, c, rows, req)
+
+		if got := fmt.Sprint(page.Items); got != step.want || page.HasMore != step.hasMore || page.Limit != 3 {
+			t.Errorf("step %d: page = %s has_more %v limit %d, want %s has_more %v", i, got, page.HasMore, page.Limit, step.want, step.hasMore)
+		}
+		if (page.NextCursor != "") != step.wantNext || (page.PrevCursor != "") != step.wantPrev {
+			t.Errorf("step %d: next %q prev %q, want next %v prev %v", i, page.NextCursor, page.PrevCursor, step.wantNext, step.wantPrev)
+		}
+	}
+}
+
+func TestPageEmpty(t *testing.T) {
+	c, _ := newTestCodec()
+	req, err := c.ParseRequest("", "", 0)
+	if err != nil {
+		t.Fatalf("ParseRequest() error = %v", err)
+	}
+	if req.Limit != types.DefaultLimit || req.Direction != Next || req.After != nil || req.Fetch() != types.DefaultLimit+1 {
+		t.Errorf("ParseRequest() = %+v", req)
+	}
+	page := listInts(t, c, nil, req)
+	if len(page.Items) != 0 || page.HasMore || page.NextCursor != "" || page.PrevCursor != "" {
+		t.Errorf("page = %+v, want empty", page)
+	}
+}
+
+func TestPageCursorIsBoundToQuery(t *testing.T) {
+	c, now := newTestCodec()
+	rows := []int{1, 2, 3, 4, 5}
+	admins := Fingerprint("id", "role=admin")
+
+	req, _ := c.ParseRequest(admins, "", 2)
+	next := listInts(t, c, rows, req).NextCursor
+	if next == "" {
+		t.Fatal("first page has no next cursor")
+	}
+
+	if _, err := c.ParseRequest(admins, next, 2); err != nil {
+		t.Errorf("ParseRequest(same query) error = %v", err)
+	}
+	if _, err := c.ParseRequest(Fingerprint("id", "role=member"), next, 2); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("ParseRequest(other filter) error = %v, want ErrInvalidCursor", err)
+	}
+	if _, err := c.ParseRequest(Fingerprint("-id", "role=admin"), next, 2); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("ParseRequest(other sort) error = %v, want ErrInvalidCursor", err)
+	}
+	*now = now.Add(DefaultTTL)
+	if _, err := c.ParseRequest(admins, next, 2); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("ParseRequest(expired) error = %v, want ErrInvalidCursor", err)
+	}
+}
@@ -0,0 +1,143 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jonnyt98/atlas-shared/contracts"
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// LimitSeats names the seat limit in ExceededError
+const LimitSeats = "seats"
+
+// MaxSeatPages caps the pages SeatsUsed reads, so a misbehaving organization
+// service cannot keep it paging forever
+const MaxSeatPages = 1000
+
+// SeatPolicy decides what happens when a user is added to a full organization
+type SeatPolicy struct {
+	// AutoIncrease buys another seat instead of refusing the user
+	AutoIncrease bool
+	// MaxSeats caps automatic increases; zero means no cap
+	MaxSeats int
+}
+
+// OrgClient is a contracts.OrganizationServiceClient that enforces the seat
+// count of organization subscriptions when users are added.
+// Every other method is passed through to the wrapped client.
+type OrgClient struct {
+	contracts.OrganizationServiceClient
+
+	subscriptions contracts.SubscriptionServiceClient
+	reserver      Reserver
+	policy        SeatPolicy
+}
+
+var _ contracts.OrganizationServiceClient = (*OrgClient)(nil)
+
+// NewOrgClient wraps orgs so adding users respects the organization's seats
+func NewOrgClient(orgs contracts.OrganizationServiceClient, subscriptions contracts.SubscriptionServiceClient, reserver Reserver, policy SeatPolicy) *OrgClient {
+	return &OrgClient{
+		OrganizationServiceClient: orgs,
+		subscriptions:             subscriptions,
+		reserver:                  reserver,
+		policy:                    policy,
+	}
+}
+
+// AddUserToOrganization adds the user if the organization has a free seat.
+// A full organization either gets another seat, when the policy allows it,
+// or the call fails with an *ExceededError. Organizations without a
+// subscription are not limited.
+// The seat is bought only once the user was added, so a failed add never leaves
+// an unused paid seat; if buying the seat fails, the user is removed again.
+func (c *OrgClient) AddUserToOrganization(ctx context.Context, orgID uuid.UUID, req types.AddUserToOrgRequest) (*types.UserResponse, error) {
+	release, err := c.reserver.Reserve(ctx, orgID.String())
+	if err != nil {
+		return nil, fmt.Errorf("quota: reserve: %w", err)
+	}
+	defer release()
+
+	sub, err := c.subscriptions.GetSubscriptionByOrgID(ctx, orgID)
+	if errors.Is(err, types.ErrNotFound) {
+		return c.OrganizationServiceClient.AddUserToOrganization(ctx, orgID, req)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("quota: load subscription: %w", err)
+	}
+
+	used, err := c.SeatsUsed(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	full := used >= sub.Seats
+	if full {
+		if err := c.canGrow(orgID, sub, used); err != nil {
+			return nil, err
+		}
+	}
+
+	user, err := c.OrganizationServiceClient.AddUserToOrganization(ctx, orgID, req)
+	if err != nil || !full {
+		return user, err
+	}
+	if _, err := c.subscriptions.UpdateSeats(ctx, sub.ID, types.SeatUpdateRequest{Seats: used + 1}); err != nil {
+		err = fmt.Errorf("quota: increase seats: %w", err)
+		if rmErr := c.OrganizationServiceClient.RemoveUserFromOrganization(ctx, orgID, user.ID); rmErr != nil {
+			err = errors.Join(err, fmt.Errorf("quota: remove user without a seat: %w", rmErr))
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// SeatsUsed counts the organization's members across every page of ListOrganizationUsers.
+// It fails if a cursor repeats, if there are more than MaxSeatPages pages, or once ctx is done.
+func (c *OrgClient) SeatsUsed(ctx context.Context, orgID uuid.UUID) (int, error) {
+	query := types.UserListQuery{Page: types.DefaultPage, Limit: types.MaxLimit}
+	seen := map[string]bool{}
+	used := 0
+	for pages := 0; ; pages++ {
+		if err := ctx.Err(); err != nil {
+			return 0, fmt.Errorf("quota: list organization users: %w", err)
+		}
+		if pages == MaxSeatPages {
+			return 0, fmt.Errorf("quota: organization %s has more than %d pages of users", orgID, MaxSeatPages)
+		}
+		page, err := c.OrganizationServiceClient.ListOrganizationUsers(ctx, orgID, query)
+		if err != nil {
+			return 0, fmt.Errorf("quota: list organization users: %w", err)
+		}
+		used += len(page.Users)
+
+		switch {
+		case page.NextCursor != "":
+			if seen[page.NextCursor] {
+				return 0, fmt.Errorf("quota: organization users cursor %q repeated", page.NextCursor)
+			}
+			seen[page.NextCursor] = true
+			query.Cursor = page.NextCursor
+		case query.Cursor == "" && query.Page < page.TotalPages:
+			query.Page++
+		default:
+			return used, nil
+		}
+	}
+}
+
+// canGrow explains why a full organization cannot buy another seat, or returns nil if it can
+func (c *OrgClient) canGrow(orgID uuid.UUID, sub *types.SubscriptionResponse, used int) error {
+	next := used + 1
+	if !c.policy.AutoIncrease || (c.policy.MaxSeats > 0 && next > c.policy.MaxSeats) {
+		return &ExceededError{
+			OrgID: &orgID,
+			Limit: LimitSeats,
+			Max:   int64(sub.Seats),
+			Used:  int64(used),
+		}
+	}
+	return nil
+}
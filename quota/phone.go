@@ -0,0 +1,112 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jonnyt98/atlas-shared/contracts"
+	"github.com/jonnyt98/atlas-shared/entitlements"
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// PhoneClient is a contracts.PhoneServiceClient that enforces the
+// entitlements.LimitPhoneNumbers limit of the user's tier on provisioning.
+// The tier is that of the user's effective subscription, which may be their organization's.
+// Every other method is passed through to the wrapped client.
+type PhoneClient struct {
+	contracts.PhoneServiceClient
+
+	users         contracts.UserServiceClient
+	subscriptions contracts.SubscriptionServiceClient
+	engine        *entitlements.Engine
+	reserver      Reserver
+}
+
+var _ contracts.PhoneServiceClient = (*PhoneClient)(nil)
+
+// NewPhoneClient wraps phones so provisioning is refused once the user's tier limit is reached
+// users resolves the organization whose subscription the user may inherit.
+func NewPhoneClient(phones contracts.PhoneServiceClient, users contracts.UserServiceClient, subscriptions contracts.SubscriptionServiceClient, engine *entitlements.Engine, reserver Reserver) *PhoneClient {
+	return &PhoneClient{
+		PhoneServiceClient: phones,
+		users:              users,
+		subscriptions:      subscriptions,
+		engine:             engine,
+		reserver:           reserver,
+	}
+}
+
+// ProvisionPhoneNumber provisions a number if the user is below their phone number limit.
+// It fails with an *ExceededError when the limit is reached.
+func (c *PhoneClient) ProvisionPhoneNumber(ctx context.Context, req types.PhoneProvisionRequest) (*types.PhoneProvisionResponse, error) {
+	release, err := c.reserver.Reserve(ctx, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("quota: reserve: %w", err)
+	}
+	defer release()
+
+	if err := c.Check(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+	return c.PhoneServiceClient.ProvisionPhoneNumber(ctx, req)
+}
+
+// Check returns an *ExceededError if the user cannot provision another number.
+// Numbers count toward the limit until they are released.
+func (c *PhoneClient) Check(ctx context.Context, userID string) error {
+	sub, err := c.Subscription(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	numbers, err := c.PhoneServiceClient.GetUserPhoneNumbers(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("quota: load phone numbers: %w", err)
+	}
+	var used int64
+	for _, number := range numbers {
+		if number.Status != types.PhoneNumberStatusReleased {
+			used++
+		}
+	}
+
+	if !c.engine.Allows(sub, entitlements.LimitPhoneNumbers, used) {
+		return &ExceededError{
+			UserID: userID,
+			Limit:  entitlements.LimitPhoneNumbers,
+			Max:    c.engine.Limit(sub, entitlements.LimitPhoneNumbers),
+			Used:   used,
+		}
+	}
+	return nil
+}
+
+// Subscription returns the user's effective subscription, resolved from their own
+// and their organization's with types.ResolveSubscription, or nil if they have neither
+func (c *PhoneClient) Subscription(ctx context.Context, userID string) (*types.SubscriptionResponse, error) {
+	user, err := c.users.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("quota: load user: %w", err)
+	}
+
+	own, err := c.subscriptions.GetSubscriptionByUserID(ctx, userID)
+	if errors.Is(err, types.ErrNotFound) {
+		own = nil
+	} else if err != nil {
+		return nil, fmt.Errorf("quota: load subscription: %w", err)
+	}
+
+	var org *types.SubscriptionResponse
+	if user.OrgID != nil {
+		org, err = c.subscriptions.GetSubscriptionByOrgID(ctx, *user.OrgID)
+		if errors.Is(err, types.ErrNotFound) {
+			org = nil
+		} else if err != nil {
+			return nil, fmt.Errorf("quota: load organization subscription: %w", err)
+		}
+	}
+
+	sub, _ := types.ResolveSubscription(own, org)
+	return sub, nil
+}
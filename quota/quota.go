@@ -0,0 +1,65 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jonnyt98/atlas-shared/internal/keylock"
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// ExceededError reports that a user or organization is at the limit of their subscription.
+// It matches types.ErrQuotaExceeded and types.ErrForbidden with errors.Is.
+type ExceededError struct {
+	UserID string
+	// OrgID is set when the limit belongs to an organization subscription
+	OrgID *uuid.UUID
+	Limit string
+	Max   int64
+	Used  int64
+}
+
+// Error implements the error interface
+func (e *ExceededError) Error() string {
+	if e.OrgID != nil {
+		return fmt.Sprintf("quota: organization %s has used %d of %d %s", e.OrgID, e.Used, e.Max, e.Limit)
+	}
+	return fmt.Sprintf("quota: user %s has used %d of %d %s", e.UserID, e.Used, e.Max, e.Limit)
+}
+
+// APIError converts the error to a quota_exceeded APIError
+func (e *ExceededError) APIError() *types.APIError {
+	return types.ErrQuotaExceeded.WithDetails(fmt.Sprintf("%s: %d of %d used", e.Limit, e.Used, e.Max))
+}
+
+// Is matches types.ErrQuotaExceeded and the generic codes it refines
+func (e *ExceededError) Is(target error) bool {
+	return e.APIError().Is(target)
+}
+
+// Reserver serializes quota checks per user, so a check and the provisioning
+// it allows complete before the next check for the same user starts.
+// Implementations shared by several instances need a distributed lock.
+type Reserver interface {
+	// Reserve blocks until the user's slot is free or ctx is done.
+	// The caller must call release once provisioning has finished.
+	Reserve(ctx context.Context, userID string) (release func(), err error)
+}
+
+// MemoryReserver is an in-process Reserver for single-instance services
+type MemoryReserver struct {
+	slots *keylock.Map
+}
+
+var _ Reserver = (*MemoryReserver)(nil)
+
+// NewMemoryReserver creates an empty in-process reserver
+func NewMemoryReserver() *MemoryReserver {
+	return &MemoryReserver{slots: keylock.New()}
+}
+
+// Reserve takes the user's slot, waiting for any provisioning in flight
+func (r *MemoryReserver) Reserve(ctx context.Context, userID string) (func(), error) {
+	return r.slots.Lock(ctx, userID)
+}
@@ -0,0 +1,299 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jonnyt98/atlas-shared/contracts"
+	"github.com/jonnyt98/atlas-shared/entitlements"
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+type fakeUsers struct {
+	contracts.UserServiceClient
+	users map[string]*types.UserResponse
+}
+
+func (f *fakeUsers) GetUserByID(ctx context.Context, id string) (*types.UserResponse, error) {
+	user, ok := f.users[id]
+	if !ok {
+		return nil, types.ErrNotFound
+	}
+	return user, nil
+}
+
+type fakeSubscriptions struct {
+	contracts.SubscriptionServiceClient
+	byUser    map[string]*types.SubscriptionResponse
+	byOrg     map[uuid.UUID]*types.SubscriptionResponse
+	seatsErr  error
+	seatCalls int
+}
+
+func (f *fakeSubscriptions) GetSubscriptionByUserID(ctx context.Context, userID string) (*types.SubscriptionResponse, error) {
+	if sub, ok := f.byUser[userID]; ok {
+		return sub, nil
+	}
+	return nil, types.ErrNotFound
+}
+
+func (f *fakeSubscriptions) GetSubscriptionByOrgID(ctx context.Context, orgID uuid.UUID) (*types.SubscriptionResponse, error) {
+	if sub, ok := f.byOrg[orgID]; ok {
+		return sub, nil
+	}
+	return nil, types.ErrNotFound
+}
+
+func (f *fakeSubscriptions) UpdateSeats(ctx context.Context, subscriptionID string, req types.SeatUpdateRequest) (*types.SubscriptionResponse, error) {
+	f.seatCalls++
+	if f.seatsErr != nil {
+		return nil, f.seatsErr
+	}
+	for _, sub := range f.byOrg {
+		if sub.ID == subscriptionID {
+			sub.Seats = req.Seats
+			return sub, nil
+		}
+	}
+	return nil, types.ErrNotFound
+}
+
+type fakePhones struct {
+	contracts.PhoneServiceClient
+	numbers     []types.PhoneNumber
+	provisioned int
+}
+
+func (f *fakePhones) GetUserPhoneNumbers(ctx context.Context, userID string) ([]types.PhoneNumber, error) {
+	return f.numbers, nil
+}
+
+func (f *fakePhones) ProvisionPhoneNumber(ctx context.Context, req types.PhoneProvisionRequest) (*types.PhoneProvisionResponse, error) {
+	f.provisioned++
+	return &types.PhoneProvisionResponse{}, nil
+}
+
+func newEngine(t *testing.T) *entitlements.Engine {
+	t.Helper()
+	engine, err := entitlements.New(entitlements.Config{
+		Tiers: map[types.SubscriptionTier]entitlements.Plan{
+			types.SubscriptionTierBasic: {Limits: map[string]int64{entitlements.LimitPhoneNumbers: 1}},
+			types.SubscriptionTierPro:   {Limits: map[string]int64{entitlements.LimitPhoneNumbers: 5}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("entitlements.New() error = %v", err)
+	}
+	return engine
+}
+
+func TestPhoneClientUsesEffectiveSubscription(t *testing.T) {
+	orgID := uuid.New()
+	active := func(tier types.SubscriptionTier) *types.SubscriptionResponse {
+		return &types.SubscriptionResponse{ID: string(tier), Tier: tier, Status: types.SubscriptionStatusActive}
+	}
+	oneNumber := []types.PhoneNumber{{Status: types.PhoneNumberStatusActive}}
+
+	tests := []struct {
+		name    string
+		orgID   *uuid.UUID
+		own     *types.SubscriptionResponse
+		org     *types.SubscriptionResponse
+		wantErr bool
+	}{
+		{"own basic at limit", nil, active(types.SubscriptionTierBasic), nil, true},
+		{"inherits pro from organization", &orgID, active(types.SubscriptionTierBasic), active(types.SubscriptionTierPro), false},
+		{"organization only", &orgID, nil, active(types.SubscriptionTierPro), false},
+		{"organization without subscription", &orgID, active(types.SubscriptionTierBasic), nil, true},
+		{"no subscription", nil, nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := &fakeUsers{users: map[string]*types.UserResponse{"u1": {ID: "u1", OrgID: tt.orgID}}}
+			subs := &fakeSubscriptions{byUser: map[string]*types.SubscriptionResponse{}, byOrg: map[uuid.UUID]*types.SubscriptionResponse{}}
+			if tt.own != nil {
+				subs.byUser["u1"] = tt.own
+			}
+			if tt.org != nil {
+				subs.byOrg[orgID] = tt.org
+			}
+			phones := &fakePhones{numbers: oneNumber}
+			client := NewPhoneClient(phones, users, subs, newEngine(t), NewMemoryReserver())
+
+			_, err := client.ProvisionPhoneNumber(context.Background(), types.PhoneProvisionRequest{UserID: "u1"})
+			if tt.wantErr {
+				var exceeded *ExceededError
+				if !errors.As(err, &exceeded) || !errors.Is(err, types.ErrQuotaExceeded) {
+					t.Fatalf("ProvisionPhoneNumber() error = %v, want *ExceededError", err)
+				}
+				if phones.provisioned != 0 {
+					t.Error("number provisioned over the limit")
+				}
+				return
+			}
+			if err != nil || phones.provisioned != 1 {
+				t.Fatalf("ProvisionPhoneNumber() error = %v, provisioned = %d", err, phones.provisioned)
+			}
+		})
+	}
+}
+
+type fakeOrgs struct {
+	contracts.OrganizationServiceClient
+	members []types.UserResponse
+	addErr  error
+	removed []string
+}
+
+func (f *fakeOrgs) ListOrganizationUsers(ctx context.Context, id uuid.UUID, query types.UserListQuery) (*types.UserListResponse, error) {
+	return &types.UserListResponse{Users: f.members, Page: 1, TotalPages: 1}, nil
+}
+
+func (f *fakeOrgs) AddUserToOrganization(ctx context.Context, orgID uuid.UUID, req types.AddUserToOrgRequest) (*types.UserResponse, error) {
+	if f.addErr != nil {
+		return nil, f.addErr
+	}
+	user := types.UserResponse{ID: req.UserID, OrgID: &orgID}
+	f.members = append(f.members, user)
+	return &user, nil
+}
+
+func (f *fakeOrgs) RemoveUserFromOrganization(ctx context.Context, orgID uuid.UUID, userID string) error {
+	f.removed = append(f.removed, userID)
+	return nil
+}
+
+func TestOrgClientBuysSeatAfterAdding(t *testing.T) {
+	orgID := uuid.New()
+	newClient := func(policy SeatPolicy) (*OrgClient, *fakeOrgs, *fakeSubscriptions) {
+		orgs := &fakeOrgs{members: []types.UserResponse{{ID: "u1"}}}
+		subs := &fakeSubscriptions{byOrg: map[uuid.UUID]*types.SubscriptionResponse{
+			orgID: {ID: "sub_org", OrgID: &orgID, Seats: 1, Status: types.SubscriptionStatusActive},
+		}}
+		return NewOrgClient(orgs, subs, NewMemoryReserver(), policy), orgs, subs
+	}
+	ctx := context.Background()
+	req := types.AddUserToOrgRequest{UserID: "u2"}
+
+	t.Run("full without auto increase", func(t *testing.T) {
+		client, orgs, subs := newClient(SeatPolicy{})
+		if _, err := client.AddUserToOrganization(ctx, orgID, req); !errors.Is(err, types.ErrQuotaExceeded) {
+			t.Fatalf("error = %v, want quota exceeded", err)
+		}
+		if len(orgs.members) != 1 || subs.seatCalls != 0 {
+			t.Errorf("members = %d, seat updates = %d, want 1, 0", len(orgs.members), subs.seatCalls)
+		}
+	})
+
+	t.Run("failed add buys no seat", func(t *testing.T) {
+		client, orgs, subs := newClient(SeatPolicy{AutoIncrease: true})
+		orgs.addErr = types.ErrConflict
+		if _, err := client.AddUserToOrganization(ctx, orgID, req); !errors.Is(err, types.ErrConflict) {
+			t.Fatalf("error = %v, want conflict", err)
+		}
+		if subs.seatCalls != 0 {
+			t.Errorf("seat updates = %d, want 0", subs.seatCalls)
+		}
+	})
+
+	t.Run("failed seat purchase removes the user", func(t *testing.T) {
+		client, orgs, subs := newClient(SeatPolicy{AutoIncrease: true})
+		subs.seatsErr = types.ErrServiceUnavailable
+		if _, err := client.AddUserToOrganization(ctx, orgID, req); !errors.Is(err, types.ErrServiceUnavailable) {
+			t.Fatalf("error = %v, want service unavailable", err)
+		}
+		if len(orgs.removed) != 1 || orgs.removed[0] != "u2" {
+			t.Errorf("removed = %v, want [u2]", orgs.removed)
+		}
+	})
+
+	t.Run("auto increase", func(t *testing.T) {
+		client, _, subs := newClient(SeatPolicy{AutoIncrease: true, MaxSeats: 2})
+		if _, err := client.AddUserToOrganization(ctx, orgID, req); err != nil {
+			t.Fatalf("error = %v", err)
+		}
+		if seats := subs.byOrg[orgID].Seats; seats != 2 {
+			t.Errorf("seats = %d, want 2", seats)
+		}
+		if _, err := client.AddUserToOrganization(ctx, orgID, types.AddUserToOrgRequest{UserID: "u3"}); !errors.Is(err, types.ErrQuotaExceeded) {
+			t.Errorf("error over MaxSeats = %v, want quota exceeded", err)
+		}
+	})
+}
+
+type pagedOrgs struct {
+	contracts.OrganizationServiceClient
+	list  func(query types.UserListQuery) *types.UserListResponse
+	calls int
+}
+
+func (f *pagedOrgs) ListOrganizationUsers(ctx context.Context, id uuid.UUID, query types.UserListQuery) (*types.UserListResponse, error) {
+	f.calls++
+	return f.list(query), nil
+}
+
+func TestSeatsUsedPages(t *testing.T) {
+	members := func(n int) []types.UserResponse { return make([]types.UserResponse, n) }
+	tests := []struct {
+		name      string
+		list      func(query types.UserListQuery) *types.UserListResponse
+		want      int
+		wantCalls int
+		wantErr   bool
+	}{
+		{"cursors", func(q types.UserListQuery) *types.UserListResponse {
+			next := map[string]string{"": "c1", "c1": "c2"}[q.Cursor]
+			return &types.UserListResponse{Users: members(2), NextCursor: next}
+		}, 6, 3, false},
+		{"page numbers", func(q types.UserListQuery) *types.UserListResponse {
+			return &types.UserListResponse{Users: members(3), Page: q.Page, TotalPages: 4}
+		}, 12, 4, false},
+		{"repeated cursor", func(q types.UserListQuery) *types.UserListResponse {
+			next := map[string]string{"": "c1", "c1": "c2", "c2": "c1"}[q.Cursor]
+			return &types.UserListResponse{Users: members(1), NextCursor: next}
+		}, 0, 3, true},
+		{"same cursor every page", func(q types.UserListQuery) *types.UserListResponse {
+			return &types.UserListResponse{Users: members(1), NextCursor: "c1"}
+		}, 0, 2, true},
+		{"endless cursors", func(q types.UserListQuery) *types.UserListResponse {
+			return &types.UserListResponse{Users: members(1), NextCursor: uuid.NewString()}
+		}, 0, MaxSeatPages, true},
+		{"endless page numbers", func(q types.UserListQuery) *types.UserListResponse {
+			return &types.UserListResponse{Page: q.Page, TotalPages: q.Page + 1}
+		}, 0, MaxSeatPages, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgs := &pagedOrgs{list: tt.list}
+			client := NewOrgClient(orgs, &fakeSubscriptions{}, NewMemoryReserver(), SeatPolicy{})
+			got, err := client.SeatsUsed(context.Background(), uuid.New())
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("SeatsUsed() = %d, %v, want %d, error %v", got, err, tt.want, tt.wantErr)
+			}
+			if orgs.calls != tt.wantCalls {
+				t.Errorf("ListOrganizationUsers called %d times, want %d", orgs.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestSeatsUsedHonorsContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	orgs := &pagedOrgs{}
+	orgs.list = func(q types.UserListQuery) *types.UserListResponse {
+		if orgs.calls == 2 {
+			cancel()
+		}
+		return &types.UserListResponse{Users: make([]types.UserResponse, 1), NextCursor: uuid.NewString()}
+	}
+	client := NewOrgClient(orgs, &fakeSubscriptions{}, NewMemoryReserver(), SeatPolicy{})
+
+	if _, err := client.SeatsUsed(ctx, uuid.New()); !errors.Is(err, context.Canceled) {
+		t.Errorf("SeatsUsed() error = %v, want context.Canceled", err)
+	}
+	if orgs.calls != 2 {
+		t.Errorf("ListOrganizationUsers called %d times after cancel, want 2", orgs.calls)
+	}
+}
@@ -0,0 +1,90 @@
+package refresh
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// MemoryStore is an in-memory Store for tests and single-instance services
+type MemoryStore struct {
+	mu     sync.Mutex
+	byID   map[string]*types.RefreshToken
+	byHash map[string]string
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		byID:   map[string]*types.RefreshToken{},
+		byHash: map[string]string{},
+	}
+}
+
+// Create stores a new token
+func (s *MemoryStore) Create(ctx context.Context, token *types.RefreshToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.put(token)
+	return nil
+}
+
+// GetByHash returns a copy of the token with the given hash
+func (s *MemoryStore) GetByHash(ctx context.Context, hash string) (*types.RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, ok := s.byHash[hash]
+	if !ok {
+		return nil, ErrTokenNotFound
+	}
+	clone := *s.byID[id]
+	return &clone, nil
+}
+
+// Rotate revokes oldID and stores next in a single step
+func (s *MemoryStore) Rotate(ctx context.Context, oldID string, next *types.RefreshToken, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.byID[oldID]
+	if !ok {
+		return ErrTokenNotFound
+	}
+	if old.RevokedAt != nil {
+		return ErrTokenRevoked
+	}
+
+	replacedBy := next.ID
+	old.RevokedAt = &at
+	old.UpdatedAt = at
+	old.ReplacedByID = &replacedBy
+	s.put(next)
+	return nil
+}
+
+// RevokeFamily revokes every unrevoked token in the family
+func (s *MemoryStore) RevokeFamily(ctx context.Context, familyID string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, token := range s.byID {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			revokedAt := at
+			token.RevokedAt = &revokedAt
+			token.UpdatedAt = at
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) put(token *types.RefreshToken) {
+	clone := *token
+	s.byID[clone.ID] = &clone
+	s.byHash[clone.TokenHash] = clone.ID
+}
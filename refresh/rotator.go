@@ -0,0 +1,140 @@
+package refresh
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// tokenBytes is the amount of randomness in each refresh token value
+const tokenBytes = 32
+
+// Rotator issues opaque refresh tokens and rotates them on every use.
+// Each login starts a token family; presenting a token that was already
+// rotated revokes the whole family, since either the legitimate client or
+// an attacker holds a stolen copy.
+type Rotator struct {
+	store Store
+	ttl   time.Duration
+
+	// Now returns the current time; defaults to time.Now
+	Now func() time.Time
+}
+
+// NewRotator creates a rotator that stores tokens in store and expires them after ttl
+func NewRotator(store Store, ttl time.Duration) *Rotator {
+	return &Rotator{store: store, ttl: ttl}
+}
+
+// HashToken returns the hex SHA-256 hash under which a token value is stored
+func HashToken(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// Issue starts a new token family for the user and returns the plaintext token value.
+// The stored token only carries the hash of the value.
+func (r *Rotator) Issue(ctx context.Context, userID string) (string, *types.RefreshToken, error) {
+	value, token, err := r.newToken(userID, uuid.NewString())
+	if err != nil {
+		return "", nil, err
+	}
+	if err := r.store.Create(ctx, token); err != nil {
+		return "", nil, fmt.Errorf("refresh: store token: %w", err)
+	}
+	return value, token, nil
+}
+
+// Rotate exchanges a presented token for a new one in the same family.
+// Reusing a rotated or revoked token revokes the family and fails with types.ErrInvalidToken.
+func (r *Rotator) Rotate(ctx context.Context, presented string) (string, *types.RefreshToken, error) {
+	current, err := r.lookup(ctx, presented)
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := r.now()
+	if current.RevokedAt != nil {
+		return "", nil, r.reuseDetected(ctx, current.FamilyID, now)
+	}
+	if !now.Before(current.ExpiresAt) {
+		return "", nil, types.ErrTokenExpired
+	}
+
+	value, next, err := r.newToken(current.UserID, current.FamilyID)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := r.store.Rotate(ctx, current.ID, next, now); err != nil {
+		if errors.Is(err, ErrTokenRevoked) {
+			// Another request rotated this token first
+			return "", nil, r.reuseDetected(ctx, current.FamilyID, now)
+		}
+		return "", nil, fmt.Errorf("refresh: rotate token: %w", err)
+	}
+	return value, next, nil
+}
+
+// Revoke revokes the family of the presented token, ending the session on logout
+func (r *Rotator) Revoke(ctx context.Context, presented string) error {
+	current, err := r.lookup(ctx, presented)
+	if err != nil {
+		return err
+	}
+	if err := r.store.RevokeFamily(ctx, current.FamilyID, r.now()); err != nil {
+		return fmt.Errorf("refresh: revoke family: %w", err)
+	}
+	return nil
+}
+
+func (r *Rotator) lookup(ctx context.Context, presented string) (*types.RefreshToken, error) {
+	token, err := r.store.GetByHash(ctx, HashToken(presented))
+	if errors.Is(err, ErrTokenNotFound) {
+		return nil, types.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("refresh: load token: %w", err)
+	}
+	return token, nil
+}
+
+func (r *Rotator) reuseDetected(ctx context.Context, familyID string, now time.Time) error {
+	if err := r.store.RevokeFamily(ctx, familyID, now); err != nil {
+		return fmt.Errorf("refresh: revoke family: %w", err)
+	}
+	return types.ErrInvalidToken.WithDetails("refresh token reuse detected")
+}
+
+func (r *Rotator) newToken(userID, familyID string) (string, *types.RefreshToken, error) {
+	raw := make([]byte, tokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, fmt.Errorf("refresh: generate token: %w", err)
+	}
+	value := base64.RawURLEncoding.EncodeToString(raw)
+
+	now := r.now()
+	return value, &types.RefreshToken{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		TokenHash: HashToken(value),
+		FamilyID:  familyID,
+		ExpiresAt: now.Add(r.ttl),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+func (r *Rotator) now() time.Time {
+	if r.Now != nil {
+		return r.Now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,73 @@
+package refresh
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jonnyt98/atlas-shared/contracts"
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// AccessTokenIssuer signs short-lived access tokens, such as *jwt.Issuer
+type AccessTokenIssuer interface {
+	IssueAccessToken(user types.UserResponse) (*types.AuthTokens, error)
+}
+
+// Sessions builds AuthResponses from a signed access token and a rotated opaque refresh token.
+// It backs the token methods of contracts.AuthServiceClient.
+type Sessions struct {
+	issuer  AccessTokenIssuer
+	rotator *Rotator
+	users   contracts.UserServiceClient
+}
+
+// NewSessions creates sessions signing access tokens with issuer, rotating refresh tokens
+// with rotator and loading users on refresh from users
+func NewSessions(issuer AccessTokenIssuer, rotator *Rotator, users contracts.UserServiceClient) *Sessions {
+	return &Sessions{issuer: issuer, rotator: rotator, users: users}
+}
+
+// Start begins a new session for an authenticated user, as on Login, Register or GoogleOAuth
+func (s *Sessions) Start(ctx context.Context, user types.UserResponse) (*types.AuthResponse, error) {
+	tokens, err := s.issuer.IssueAccessToken(user)
+	if err != nil {
+		return nil, err
+	}
+	tokens.RefreshToken, _, err = s.rotator.Issue(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &types.AuthResponse{AuthTokens: *tokens, User: user}, nil
+}
+
+// Refresh implements AuthServiceClient.RefreshToken.
+// The presented token is invalidated, and presenting it again revokes the session.
+func (s *Sessions) Refresh(ctx context.Context, req types.RefreshTokenRequest) (*types.AuthResponse, error) {
+	value, token, err := s.rotator.Rotate(ctx, req.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.users.GetUserByID(ctx, token.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("refresh: load user: %w", err)
+	}
+	return s.respond(*user, value)
+}
+
+// End implements AuthServiceClient.Logout by revoking the session of the presented token.
+// Requests without a token are a no-op.
+func (s *Sessions) End(ctx context.Context, req types.LogoutRequest) error {
+	if req.RefreshToken == "" {
+		return nil
+	}
+	return s.rotator.Revoke(ctx, req.RefreshToken)
+}
+
+func (s *Sessions) respond(user types.UserResponse, refreshToken string) (*types.AuthResponse, error) {
+	tokens, err := s.issuer.IssueAccessToken(user)
+	if err != nil {
+		return nil, err
+	}
+	tokens.RefreshToken = refreshToken
+	return &types.AuthResponse{AuthTokens: *tokens, User: user}, nil
+}
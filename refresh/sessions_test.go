@@ -0,0 +1,102 @@
+package refresh_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonnyt98/atlas-shared/contracts"
+	"github.com/jonnyt98/atlas-shared/jwt"
+	"github.com/jonnyt98/atlas-shared/refresh"
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+type fakeUsers struct {
+	contracts.UserServiceClient
+	user types.UserResponse
+}
+
+func (f fakeUsers) GetUserByID(ctx context.Context, id string) (*types.UserResponse, error) {
+	if id != f.user.ID {
+		return nil, types.ErrNotFound
+	}
+	return &f.user, nil
+}
+
+func TestSessionsRotateRefreshTokens(t *testing.T) {
+	ctx := context.Background()
+	user := types.UserResponse{ID: "u1", Email: "a@example.com", Role: "user"}
+	key := jwt.NewHS256Key([]byte("test-secret"))
+	issuer := jwt.NewIssuer(key, 15*time.Minute, 24*time.Hour)
+	sessions := refresh.NewSessions(issuer, refresh.NewRotator(refresh.NewMemoryStore(), 24*time.Hour), fakeUsers{user: user})
+
+	login, err := sessions.Start(ctx, user)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if login.User.ID != user.ID || login.AccessToken == "" || login.RefreshToken == "" {
+		t.Fatalf("Start() = %+v", login)
+	}
+	if _, err := jwt.NewVerifier(key).Verify(login.AccessToken, types.TokenTypeAccess); err != nil {
+		t.Errorf("access token does not verify: %v", err)
+	}
+
+	refreshed, err := sessions.Refresh(ctx, types.RefreshTokenRequest{RefreshToken: login.RefreshToken})
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if refreshed.RefreshToken == login.RefreshToken || refreshed.User.Email != user.Email {
+		t.Errorf("Refresh() = %+v", refreshed)
+	}
+
+	// Reusing the old token revokes the family, including the token it was rotated into
+	if _, err := sessions.Refresh(ctx, types.RefreshTokenRequest{RefreshToken: login.RefreshToken}); !errors.Is(err, types.ErrInvalidToken) {
+		t.Errorf("Refresh(reused) error = %v, want invalid token", err)
+	}
+	if _, err := sessions.Refresh(ctx, types.RefreshTokenRequest{RefreshToken: refreshed.RefreshToken}); !errors.Is(err, types.ErrInvalidToken) {
+		t.Errorf("Refresh(after reuse) error = %v, want invalid token", err)
+	}
+}
+
+func TestSessionsEnd(t *testing.T) {
+	ctx := context.Background()
+	user := types.UserResponse{ID: "u1"}
+	issuer := jwt.NewIssuer(jwt.NewHS256Key([]byte("test-secret")), time.Minute, time.Hour)
+	sessions := refresh.NewSessions(issuer, refresh.NewRotator(refresh.NewMemoryStore(), time.Hour), fakeUsers{user: user})
+
+	login, err := sessions.Start(ctx, user)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := sessions.End(ctx, types.LogoutRequest{}); err != nil {
+		t.Errorf("End(no token) error = %v", err)
+	}
+	if err := sessions.End(ctx, types.LogoutRequest{RefreshToken: login.RefreshToken}); err != nil {
+		t.Fatalf("End() error = %v", err)
+	}
+	if _, err := sessions.Refresh(ctx, types.RefreshTokenRequest{RefreshToken: login.RefreshToken}); !errors.Is(err, types.ErrInvalidToken) {
+		t.Errorf("Refresh(after logout) error = %v, want invalid token", err)
+	}
+}
+
+func TestRefreshTokenHashIsNotSerialized(t *testing.T) {
+	hash := refresh.HashToken("value")
+	data, err := json.Marshal(types.RefreshToken{ID: "t1", TokenHash: hash})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), hash) || strings.Contains(string(data), "token_hash") {
+		t.Errorf("Marshal() = %s, exposes the token hash", data)
+	}
+
+	var decoded types.RefreshToken
+	if err := json.Unmarshal([]byte(`{"id":"t1","token_hash":"`+hash+`"}`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.TokenHash != "" {
+		t.Errorf("Unmarshal() set TokenHash = %q from client input", decoded.TokenHash)
+	}
+}
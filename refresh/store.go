@@ -0,0 +1,29 @@
+package refresh
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// Store errors
+var (
+	ErrTokenNotFound = errors.New("refresh: token not found")
+	ErrTokenRevoked  = errors.New("refresh: token already revoked")
+)
+
+// Store persists refresh tokens by hash.
+// Implementations must make Rotate atomic so a token can only be rotated once.
+type Store interface {
+	// Create stores a new token
+	Create(ctx context.Context, token *types.RefreshToken) error
+	// GetByHash returns the token with the given hash, or ErrTokenNotFound
+	GetByHash(ctx context.Context, hash string) (*types.RefreshToken, error)
+	// Rotate revokes the token with oldID and stores next as its replacement.
+	// It returns ErrTokenRevoked if oldID was already revoked.
+	Rotate(ctx context.Context, oldID string, next *types.RefreshToken, at time.Time) error
+	// RevokeFamily revokes every unrevoked token in the family
+	RevokeFamily(ctx context.Context, familyID string, at time.Time) error
+}
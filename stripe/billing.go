@@ -0,0 +1,82 @@
+package stripe
+
+import (
+	"strings"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// ToInvoice maps the Stripe invoice onto the shared invoice type.
+// UserID and SubscriptionID are left for the caller to resolve from its own records.
+// Only the line items included in the payload are mapped.
+func (i *Invoice) ToInvoice() types.Invoice {
+	lines := make([]types.InvoiceLineItem, 0, len(i.Lines.Data))
+	for _, line := range i.Lines.Data {
+		lines = append(lines, line.ToLineItem())
+	}
+	return types.Invoice{
+		StripeInvoiceID:  i.ID,
+		Number:           i.Number,
+		Status:           types.InvoiceStatus(i.Status),
+		Currency:         strings.ToLower(i.Currency),
+		SubtotalCents:    i.Subtotal,
+		TaxCents:         i.Tax,
+		TotalCents:       i.Total,
+		AmountDueCents:   i.AmountDue,
+		AmountPaidCents:  i.AmountPaid,
+		Lines:            lines,
+		PeriodStart:      unixTime(i.PeriodStart),
+		PeriodEnd:        unixTime(i.PeriodEnd),
+		HostedInvoiceURL: i.HostedInvoiceURL,
+		PDFURL:           i.InvoicePDF,
+		PaidAt:           unixTimePtr(i.StatusTransitions.PaidAt),
+		CreatedAt:        unixTime(i.Created),
+	}
+}
+
+// ToLineItem maps the Stripe invoice line onto the shared line item type
+func (l *InvoiceLine) ToLineItem() types.InvoiceLineItem {
+	item := types.InvoiceLineItem{
+		ID:          l.ID,
+		Type:        l.lineType(),
+		Description: l.Description,
+		Quantity:    l.Quantity,
+		AmountCents: l.Amount,
+		Currency:    strings.ToLower(l.Currency),
+		PeriodStart: unixTime(l.Period.Start),
+		PeriodEnd:   unixTime(l.Period.End),
+	}
+	if l.Price != nil {
+		item.UnitAmountCents = l.Price.UnitAmount
+	}
+	return item
+}
+
+// ToPaymentMethod maps the Stripe payment method onto the shared type.
+// IsDefault is left for the caller, since it is a property of the customer.
+func (p *PaymentMethod) ToPaymentMethod() types.PaymentMethod {
+	method := types.PaymentMethod{
+		ID:        p.ID,
+		Type:      p.Type,
+		CreatedAt: unixTime(p.Created),
+	}
+	if p.Card != nil {
+		method.Brand = p.Card.Brand
+		method.Last4 = p.Card.Last4
+		method.ExpMonth = p.Card.ExpMonth
+		method.ExpYear = p.Card.ExpYear
+	}
+	return method
+}
+
+func (l *InvoiceLine) lineType() types.InvoiceLineType {
+	switch {
+	case l.Proration:
+		return types.InvoiceLineProration
+	case l.Price != nil && l.Price.Recurring != nil && l.Price.Recurring.UsageType == "metered":
+		return types.InvoiceLineMetered
+	case l.Type == "subscription":
+		return types.InvoiceLineSubscription
+	}
+	return types.InvoiceLineOneOff
+}
@@ -0,0 +1,111 @@
+package stripe
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+func TestInvoiceLineTypes(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want types.InvoiceLineType
+	}{
+		{"subscription", `{"type":"subscription","price":{"recurring":{"interval":"month","usage_type":"licensed"}}}`, types.InvoiceLineSubscription},
+		{"metered", `{"type":"subscription","price":{"recurring":{"interval":"month","usage_type":"metered"}}}`, types.InvoiceLineMetered},
+		{"proration", `{"type":"subscription","proration":true,"price":{"recurring":{"interval":"month","usage_type":"licensed"}}}`, types.InvoiceLineProration},
+		{"metered proration", `{"type":"subscription","proration":true,"price":{"recurring":{"interval":"month","usage_type":"metered"}}}`, types.InvoiceLineProration},
+		{"invoice item", `{"type":"invoiceitem","price":{"unit_amount":500}}`, types.InvoiceLineOneOff},
+		{"no price", `{"type":"invoiceitem"}`, types.InvoiceLineOneOff},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var line InvoiceLine
+			if err := json.Unmarshal([]byte(tt.line), &line); err != nil {
+				t.Fatal(err)
+			}
+			if got := line.ToLineItem().Type; got != tt.want {
+				t.Errorf("ToLineItem().Type = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvoiceLineAmounts(t *testing.T) {
+	tests := []struct {
+		name         string
+		line         string
+		wantAmount   int64
+		wantUnit     int64
+		wantQuantity int64
+	}{
+		// Stripe has already rounded the line amount; it is kept as sent
+		{"per unit", `{"amount":2997,"quantity":3,"price":{"unit_amount":999}}`, 2997, 999, 3},
+		{"proration credit", `{"amount":-1033,"quantity":1,"proration":true,"price":{"unit_amount":3100}}`, -1033, 3100, 1},
+		// Tiered prices have no single unit amount
+		{"tiered", `{"amount":4250,"quantity":1200,"price":{"recurring":{"interval":"month","usage_type":"metered"}}}`, 4250, 0, 1200},
+		{"no price", `{"amount":500,"quantity":1}`, 500, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var line InvoiceLine
+			if err := json.Unmarshal([]byte(tt.line), &line); err != nil {
+				t.Fatal(err)
+			}
+			got := line.ToLineItem()
+			if got.AmountCents != tt.wantAmount || got.UnitAmountCents != tt.wantUnit || got.Quantity != tt.wantQuantity {
+				t.Errorf("ToLineItem() amount, unit, quantity = %d, %d, %d, want %d, %d, %d",
+					got.AmountCents, got.UnitAmountCents, got.Quantity, tt.wantAmount, tt.wantUnit, tt.wantQuantity)
+			}
+		})
+	}
+}
+
+func TestToInvoice(t *testing.T) {
+	payload := `{
+	  "id": "in_1", "number": "A-0001", "status": "paid", "currency": "USD",
+	  "subtotal": 3000, "tax": 240, "total": 3240, "amount_due": 3240, "amount_paid": 3240,
+	  "period_start": 1775001600, "period_end": 1777593600, "created": 1777593700,
+	  "status_transitions": {"paid_at": 1777597200},
+	  "lines": {"data": [
+	    {"id": "il_1", "type": "subscription", "description": "Pro", "amount": 3000, "currency": "usd", "quantity": 1,
+	     "period": {"start": 1775001600, "end": 1777593600}, "price": {"unit_amount": 3000, "recurring": {"interval": "month"}}}
+	  ]}
+	}`
+	var invoice Invoice
+	if err := json.Unmarshal([]byte(payload), &invoice); err != nil {
+		t.Fatal(err)
+	}
+	got := invoice.ToInvoice()
+
+	start, end := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	if got.StripeInvoiceID != "in_1" || got.Status != types.InvoiceStatusPaid || got.Currency != "usd" {
+		t.Errorf("ToInvoice() = %+v", got)
+	}
+	if got.SubtotalCents != 3000 || got.TaxCents != 240 || got.TotalCents != 3240 || got.AmountDueCents != 3240 || got.AmountPaidCents != 3240 {
+		t.Errorf("ToInvoice() amounts = %+v", got)
+	}
+	if !got.PeriodStart.Equal(start) || !got.PeriodEnd.Equal(end) || got.PaidAt == nil || !got.PaidAt.Equal(end.Add(time.Hour)) {
+		t.Errorf("ToInvoice() period %s - %s, paid at %v", got.PeriodStart, got.PeriodEnd, got.PaidAt)
+	}
+	if len(got.Lines) != 1 || got.Lines[0].Type != types.InvoiceLineSubscription || !got.Lines[0].PeriodEnd.Equal(end) {
+		t.Errorf("ToInvoice() lines = %+v", got.Lines)
+	}
+}
+
+func TestToInvoiceEmpty(t *testing.T) {
+	var invoice Invoice
+	if err := json.Unmarshal([]byte(`{"id":"in_1","status":"draft","currency":"usd","lines":{"data":[]}}`), &invoice); err != nil {
+		t.Fatal(err)
+	}
+	got := invoice.ToInvoice()
+	if got.Lines == nil || len(got.Lines) != 0 {
+		t.Errorf("Lines = %#v, want an empty list", got.Lines)
+	}
+	if !got.PeriodStart.IsZero() || !got.PeriodEnd.IsZero() || got.PaidAt != nil || got.TotalCents != 0 {
+		t.Errorf("ToInvoice() = %+v, want zero period, amounts and paid at", got)
+	}
+}
@@ -0,0 +1,185 @@
+package stripe
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// Invoice billing reasons that decide whether an invoice event moves the subscription status
+const (
+	BillingReasonSubscriptionCreate = "subscription_create"
+	BillingReasonSubscriptionCycle  = "subscription_cycle"
+	BillingReasonSubscriptionUpdate = "subscription_update"
+)
+
+// Supported event types
+const (
+	EventSubscriptionCreated  = "customer.subscription.created"
+	EventSubscriptionUpdated  = "customer.subscription.updated"
+	EventSubscriptionDeleted  = "customer.subscription.deleted"
+	EventInvoicePaid          = "invoice.paid"
+	EventInvoicePaymentFailed = "invoice.payment_failed"
+)
+
+// Event is a Stripe webhook event with its data object left raw until requested
+type Event struct {
+	ID       string    `json:"id"`
+	Object   string    `json:"object"`
+	Type     string    `json:"type"`
+	Created  int64     `json:"created"`
+	Livemode bool      `json:"livemode"`
+	Data     EventData `json:"data"`
+}
+
+// EventData holds the object the event is about
+type EventData struct {
+	Object             json.RawMessage `json:"object"`
+	PreviousAttributes json.RawMessage `json:"previous_attributes,omitempty"`
+}
+
+// ParseEvent decodes a webhook payload without verifying its signature
+func ParseEvent(payload []byte) (*Event, error) {
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("stripe: decode event: %w", err)
+	}
+	if event.ID == "" || event.Type == "" {
+		return nil, fmt.Errorf("stripe: event is missing id or type")
+	}
+	return &event, nil
+}
+
+// CreatedAt returns the event creation time
+func (e *Event) CreatedAt() time.Time {
+	return time.Unix(e.Created, 0).UTC()
+}
+
+// IsSubscriptionEvent reports whether the event carries a subscription object
+func (e *Event) IsSubscriptionEvent() bool {
+	switch e.Type {
+	case EventSubscriptionCreated, EventSubscriptionUpdated, EventSubscriptionDeleted:
+		return true
+	}
+	return false
+}
+
+// IsInvoiceEvent reports whether the event carries an invoice object
+func (e *Event) IsInvoiceEvent() bool {
+	switch e.Type {
+	case EventInvoicePaid, EventInvoicePaymentFailed:
+		return true
+	}
+	return false
+}
+
+// Subscription decodes the subscription object of a customer.subscription.* event
+func (e *Event) Subscription() (*Subscription, error) {
+	if !e.IsSubscriptionEvent() {
+		return nil, fmt.Errorf("stripe: %s event has no subscription object", e.Type)
+	}
+	var sub Subscription
+	if err := json.Unmarshal(e.Data.Object, &sub); err != nil {
+		return nil, fmt.Errorf("stripe: decode subscription: %w", err)
+	}
+	return &sub, nil
+}
+
+// Invoice decodes the invoice object of an invoice.* event
+func (e *Event) Invoice() (*Invoice, error) {
+	if !e.IsInvoiceEvent() {
+		return nil, fmt.Errorf("stripe: %s event has no invoice object", e.Type)
+	}
+	var invoice Invoice
+	if err := json.Unmarshal(e.Data.Object, &invoice); err != nil {
+		return nil, fmt.Errorf("stripe: decode invoice: %w", err)
+	}
+	return &invoice, nil
+}
+
+// SubscriptionID returns the Stripe subscription ID the event is about, or "" if it has none
+func (e *Event) SubscriptionID() string {
+	switch {
+	case e.IsSubscriptionEvent():
+		if sub, err := e.Subscription(); err == nil {
+			return sub.ID
+		}
+	case e.IsInvoiceEvent():
+		if invoice, err := e.Invoice(); err == nil {
+			return invoice.Subscription.ID
+		}
+	}
+	return ""
+}
+
+// SubscriptionUpdate returns the Stripe subscription ID the event affects and the update it implies.
+// Subscription events carry the full new state. Invoice events only move the status, and only when
+// the invoice settles a subscription charge: a paid non-zero subscription invoice means active, and
+// a failed renewal means past_due. Other invoices, such as the $0 invoice opening a trial or a failed
+// first payment, leave the status to the customer.subscription.updated event Stripe sends with them.
+func (e *Event) SubscriptionUpdate() (string, types.SubscriptionUpdateRequest, error) {
+	switch {
+	case e.IsSubscriptionEvent():
+		sub, err := e.Subscription()
+		if err != nil {
+			return "", types.SubscriptionUpdateRequest{}, err
+		}
+		req := sub.UpdateRequest()
+		if e.Type == EventSubscriptionDeleted {
+			status := types.SubscriptionStatusCanceled
+			req.Status = &status
+			if req.CanceledAt == nil {
+				canceledAt := e.CreatedAt()
+				req.CanceledAt = &canceledAt
+			}
+		}
+		return sub.ID, req, nil
+
+	case e.IsInvoiceEvent():
+		invoice, err := e.Invoice()
+		if err != nil {
+			return "", types.SubscriptionUpdateRequest{}, err
+		}
+		if invoice.Subscription.ID == "" {
+			return "", types.SubscriptionUpdateRequest{}, fmt.Errorf("stripe: invoice %s has no subscription", invoice.ID)
+		}
+		var req types.SubscriptionUpdateRequest
+		if status, ok := invoiceStatus(e.Type, invoice); ok {
+			req.Status = &status
+		}
+		return invoice.Subscription.ID, req, nil
+	}
+	return "", types.SubscriptionUpdateRequest{}, fmt.Errorf("stripe: unsupported event type %s", e.Type)
+}
+
+// invoiceStatus returns the subscription status an invoice event implies, if any
+func invoiceStatus(eventType string, invoice *Invoice) (types.SubscriptionStatus, bool) {
+	switch eventType {
+	case EventInvoicePaid:
+		switch invoice.BillingReason {
+		case BillingReasonSubscriptionCreate, BillingReasonSubscriptionCycle, BillingReasonSubscriptionUpdate:
+			if invoice.AmountPaid > 0 {
+				return types.SubscriptionStatusActive, true
+			}
+		}
+	case EventInvoicePaymentFailed:
+		switch invoice.BillingReason {
+		case BillingReasonSubscriptionCycle, BillingReasonSubscriptionUpdate:
+			return types.SubscriptionStatusPastDue, true
+		}
+	}
+	return "", false
+}
+
+// Raw converts the event to the loosely typed shared representation
+func (e *Event) Raw() types.StripeWebhookEvent {
+	return types.StripeWebhookEvent{
+		ID:      e.ID,
+		Type:    e.Type,
+		Created: e.Created,
+		Data:    e.Data,
+		Object:  e.Object,
+	}
+}
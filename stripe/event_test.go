@@ -0,0 +1,86 @@
+package stripe
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+func invoiceEvent(t *testing.T, eventType, billingReason string, amountPaid int64) *Event {
+	t.Helper()
+	payload := fmt.Sprintf(`{"id":"evt_1","type":%q,"created":1700000000,"data":{"object":{"id":"in_1","subscription":"sub_1","billing_reason":%q,"amount_paid":%d}}}`,
+		eventType, billingReason, amountPaid)
+	event, err := ParseEvent([]byte(payload))
+	if err != nil {
+		t.Fatalf("ParseEvent() error = %v", err)
+	}
+	return event
+}
+
+func TestInvoiceEventStatus(t *testing.T) {
+	active := types.SubscriptionStatusActive
+	pastDue := types.SubscriptionStatusPastDue
+
+	tests := []struct {
+		name          string
+		eventType     string
+		billingReason string
+		amountPaid    int64
+		want          *types.SubscriptionStatus
+	}{
+		{"first payment", EventInvoicePaid, BillingReasonSubscriptionCreate, 1000, &active},
+		{"renewal paid", EventInvoicePaid, BillingReasonSubscriptionCycle, 1000, &active},
+		{"plan change paid", EventInvoicePaid, BillingReasonSubscriptionUpdate, 500, &active},
+		{"trial invoice", EventInvoicePaid, BillingReasonSubscriptionCreate, 0, nil},
+		{"manual invoice", EventInvoicePaid, "manual", 1000, nil},
+		{"renewal failed", EventInvoicePaymentFailed, BillingReasonSubscriptionCycle, 0, &pastDue},
+		{"first payment failed", EventInvoicePaymentFailed, BillingReasonSubscriptionCreate, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, req, err := invoiceEvent(t, tt.eventType, tt.billingReason, tt.amountPaid).SubscriptionUpdate()
+			if err != nil {
+				t.Fatalf("SubscriptionUpdate() error = %v", err)
+			}
+			if id != "sub_1" {
+				t.Errorf("subscription ID = %q", id)
+			}
+			switch {
+			case tt.want == nil && req.Status != nil:
+				t.Errorf("Status = %q, want unchanged", *req.Status)
+			case tt.want != nil && (req.Status == nil || *req.Status != *tt.want):
+				t.Errorf("Status = %v, want %q", req.Status, *tt.want)
+			}
+		})
+	}
+}
+
+func TestSubscriptionDeletedEvent(t *testing.T) {
+	payload := `{"id":"evt_2","type":"customer.subscription.deleted","created":1700000000,"data":{"object":{"id":"sub_1","customer":"cus_1","status":"canceled"}}}`
+	event, err := ParseEvent([]byte(payload))
+	if err != nil {
+		t.Fatalf("ParseEvent() error = %v", err)
+	}
+	id, req, err := event.SubscriptionUpdate()
+	if err != nil {
+		t.Fatalf("SubscriptionUpdate() error = %v", err)
+	}
+	if id != "sub_1" || req.Status == nil || *req.Status != types.SubscriptionStatusCanceled {
+		t.Errorf("SubscriptionUpdate() = %q, %+v", id, req)
+	}
+	if req.CanceledAt == nil || !req.CanceledAt.Equal(event.CreatedAt()) {
+		t.Errorf("CanceledAt = %v, want the event time", req.CanceledAt)
+	}
+	if req.StripeCustomerID == nil || *req.StripeCustomerID != "cus_1" {
+		t.Errorf("StripeCustomerID = %v", req.StripeCustomerID)
+	}
+}
+
+func TestParseEventRejectsIncompletePayloads(t *testing.T) {
+	for _, payload := range []string{`{`, `{"id":"evt_1"}`, `{"type":"invoice.paid"}`} {
+		if _, err := ParseEvent([]byte(payload)); err == nil {
+			t.Errorf("ParseEvent(%s) error = nil", payload)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package stripe
+
+import (
+	"context"
+
+	"github.com/jonnyt98/atlas-shared/internal/keylock"
+)
+
+// Locker serializes event processing per subscription, so the check of the newest applied event,
+// the handler and the advance run as one step.
+type Locker interface {
+	// Lock blocks until the subscription is free or ctx is done.
+	// The caller must call unlock once the event has been processed.
+	Lock(ctx context.Context, subscriptionID string) (unlock func(), err error)
+}
+
+// MemoryLocker is an in-process Locker for single-instance services
+type MemoryLocker struct {
+	locks *keylock.Map
+}
+
+var _ Locker = (*MemoryLocker)(nil)
+
+// NewMemoryLocker creates an empty in-process locker
+func NewMemoryLocker() *MemoryLocker {
+	return &MemoryLocker{locks: keylock.New()}
+}
+
+// Lock takes the subscription's lock, waiting for any event in flight
+func (l *MemoryLocker) Lock(ctx context.Context, subscriptionID string) (func(), error) {
+	return l.locks.Lock(ctx, subscriptionID)
+}
@@ -0,0 +1,119 @@
+package stripe
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-memory EventStore and DeadLetterStore for tests and single-instance services.
+// Processed event IDs are never evicted.
+type MemoryStore struct {
+	mu          sync.Mutex
+	processed   map[string]time.Time
+	lastApplied map[string]Position
+	deadLetters map[string]*DeadLetter
+}
+
+var (
+	_ EventStore      = (*MemoryStore)(nil)
+	_ DeadLetterStore = (*MemoryStore)(nil)
+)
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		processed:   map[string]time.Time{},
+		lastApplied: map[string]Position{},
+		deadLetters: map[string]*DeadLetter{},
+	}
+}
+
+// Claim marks the event as processed and reports false if it already was
+func (s *MemoryStore) Claim(ctx context.Context, eventID string, at time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.processed[eventID]; ok {
+		return false, nil
+	}
+	s.processed[eventID] = at
+	return true, nil
+}
+
+// Release forgets a claimed event
+func (s *MemoryStore) Release(ctx context.Context, eventID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.processed, eventID)
+	return nil
+}
+
+// LastApplied returns the position of the newest event applied to the subscription
+func (s *MemoryStore) LastApplied(ctx context.Context, subscriptionID string) (Position, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastApplied[subscriptionID], nil
+}
+
+// Advance records pos as applied unless a newer event already was
+func (s *MemoryStore) Advance(ctx context.Context, subscriptionID string, pos Position) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.lastApplied[subscriptionID].Before(pos) {
+		s.lastApplied[subscriptionID] = pos
+	}
+	return nil
+}
+
+// Put stores a copy of the dead letter
+func (s *MemoryStore) Put(ctx context.Context, letter *DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clone := *letter
+	s.deadLetters[clone.Event.ID] = &clone
+	return nil
+}
+
+// Get returns a copy of the dead letter for the event ID
+func (s *MemoryStore) Get(ctx context.Context, eventID string) (*DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	letter, ok := s.deadLetters[eventID]
+	if !ok {
+		return nil, ErrDeadLetterNotFound
+	}
+	clone := *letter
+	return &clone, nil
+}
+
+// List returns copies of every dead letter, oldest failure first
+func (s *MemoryStore) List(ctx context.Context) ([]*DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	letters := make([]*DeadLetter, 0, len(s.deadLetters))
+	for _, letter := range s.deadLetters {
+		clone := *letter
+		letters = append(letters, &clone)
+	}
+	sort.Slice(letters, func(i, j int) bool {
+		return letters[i].FailedAt.Before(letters[j].FailedAt)
+	})
+	return letters, nil
+}
+
+// Delete removes the dead letter for the event ID
+func (s *MemoryStore) Delete(ctx context.Context, eventID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.deadLetters, eventID)
+	return nil
+}
@@ -0,0 +1,322 @@
+package stripe
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// Metadata keys read from subscriptions and prices
+const (
+	MetadataUserID = "user_id"
+	MetadataOrgID  = "org_id"
+	MetadataTier   = "tier"
+)
+
+// Expandable is a reference Stripe sends either as a bare ID or as the expanded object
+type Expandable struct {
+	ID string
+}
+
+// UnmarshalJSON accepts "sub_123", {"id": "sub_123", ...} and null
+func (e *Expandable) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		e.ID = ""
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &e.ID)
+	}
+	var object struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return fmt.Errorf("stripe: decode expandable reference: %w", err)
+	}
+	e.ID = object.ID
+	return nil
+}
+
+// MarshalJSON encodes the reference as its ID
+func (e Expandable) MarshalJSON() ([]byte, error) {
+	if e.ID == "" {
+		return []byte("null"), nil
+	}
+	return json.Marshal(e.ID)
+}
+
+// Price is the subset of a Stripe price used to derive plan, tier and line items
+type Price struct {
+	ID         string            `json:"id"`
+	LookupKey  string            `json:"lookup_key,omitempty"`
+	Nickname   string            `json:"nickname,omitempty"`
+	UnitAmount int64             `json:"unit_amount,omitempty"`
+	Recurring  *Recurring        `json:"recurring,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+}
+
+// Recurring describes how a recurring price is billed
+type Recurring struct {
+	Interval      string `json:"interval"`
+	IntervalCount int    `json:"interval_count"`
+	UsageType     string `json:"usage_type"`
+}
+
+// SubscriptionItem is one line of a subscription
+type SubscriptionItem struct {
+	ID       string `json:"id"`
+	Price    Price  `json:"price"`
+	Quantity int    `json:"quantity"`
+}
+
+// Subscription is the data object of customer.subscription.* events
+type Subscription struct {
+	ID                 string            `json:"id"`
+	Customer           Expandable        `json:"customer"`
+	Status             string            `json:"status"`
+	StartDate          int64             `json:"start_date"`
+	CurrentPeriodStart int64             `json:"current_period_start"`
+	CurrentPeriodEnd   int64             `json:"current_period_end"`
+	BillingCycleAnchor int64             `json:"billing_cycle_anchor"`
+	CanceledAt         int64             `json:"canceled_at,omitempty"`
+	TrialEnd           int64             `json:"trial_end,omitempty"`
+	Metadata           map[string]string `json:"metadata,omitempty"`
+	Items              struct {
+		Data []SubscriptionItem `json:"data"`
+	} `json:"items"`
+}
+
+// Invoice is the data object of invoice.* events
+type Invoice struct {
+	ID                string       `json:"id"`
+	Number            string       `json:"number,omitempty"`
+	Customer          Expandable   `json:"customer"`
+	Subscription      Expandable   `json:"subscription"`
+	Status            string       `json:"status"`
+	BillingReason     string       `json:"billing_reason,omitempty"`
+	Currency          string       `json:"currency"`
+	Subtotal          int64        `json:"subtotal"`
+	Tax               int64        `json:"tax"`
+	Total             int64        `json:"total"`
+	AmountDue         int64        `json:"amount_due"`
+	AmountPaid        int64        `json:"amount_paid"`
+	PeriodStart       int64        `json:"period_start"`
+	PeriodEnd         int64        `json:"period_end"`
+	HostedInvoiceURL  string       `json:"hosted_invoice_url,omitempty"`
+	InvoicePDF        string       `json:"invoice_pdf,omitempty"`
+	Created           int64        `json:"created"`
+	Lines             InvoiceLines `json:"lines"`
+	StatusTransitions struct {
+		PaidAt int64 `json:"paid_at,omitempty"`
+	} `json:"status_transitions"`
+}
+
+// InvoiceLines is the first page of an invoice's line items
+type InvoiceLines struct {
+	Data    []InvoiceLine `json:"data"`
+	HasMore bool          `json:"has_more"`
+}
+
+// InvoiceLine is one line item of an invoice
+type InvoiceLine struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Amount      int64  `json:"amount"`
+	Currency    string `json:"currency"`
+	Quantity    int64  `json:"quantity"`
+	Proration   bool   `json:"proration"`
+	Period      struct {
+		Start int64 `json:"start"`
+		End   int64 `json:"end"`
+	} `json:"period"`
+	Price *Price `json:"price,omitempty"`
+}
+
+// PaymentMethod is a Stripe payment method object
+type PaymentMethod struct {
+	ID       string     `json:"id"`
+	Type     string     `json:"type"`
+	Customer Expandable `json:"customer"`
+	Created  int64      `json:"created"`
+	Card     *struct {
+		Brand    string `json:"brand"`
+		Last4    string `json:"last4"`
+		ExpMonth int    `json:"exp_month"`
+		ExpYear  int    `json:"exp_year"`
+	} `json:"card,omitempty"`
+}
+
+// Price returns the price of the first subscription item
+func (s *Subscription) Price() (Price, bool) {
+	if len(s.Items.Data) == 0 {
+		return Price{}, false
+	}
+	return s.Items.Data[0].Price, true
+}
+
+// Plan returns the price lookup key, falling back to the price ID
+func (s *Subscription) Plan() string {
+	price, ok := s.Price()
+	if !ok {
+		return ""
+	}
+	if price.LookupKey != "" {
+		return price.LookupKey
+	}
+	return price.ID
+}
+
+// OrgID returns the owning organization from the "org_id" metadata key, or nil for user subscriptions
+func (s *Subscription) OrgID() (*uuid.UUID, error) {
+	value := s.Metadata[MetadataOrgID]
+	if value == "" {
+		return nil, nil
+	}
+	orgID, err := uuid.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("stripe: subscription %s has invalid %s metadata: %w", s.ID, MetadataOrgID, err)
+	}
+	return &orgID, nil
+}
+
+// Seats returns the quantity of the first subscription item
+func (s *Subscription) Seats() int {
+	if len(s.Items.Data) == 0 {
+		return 0
+	}
+	return s.Items.Data[0].Quantity
+}
+
+// Tier returns the tier from subscription metadata, then price metadata, then the price lookup key
+func (s *Subscription) Tier() types.SubscriptionTier {
+	if tier, ok := parseTier(s.Metadata[MetadataTier]); ok {
+		return tier
+	}
+	price, _ := s.Price()
+	if tier, ok := parseTier(price.Metadata[MetadataTier]); ok {
+		return tier
+	}
+	tier, _ := parseTier(price.LookupKey)
+	return tier
+}
+
+// BillingCycle returns the renewal cycle of the first item's recurring price anchored at the
+// billing cycle anchor, or nil when the subscription has no recurring price or anchor
+func (s *Subscription) BillingCycle() *types.BillingCycle {
+	price, ok := s.Price()
+	if !ok || price.Recurring == nil || s.BillingCycleAnchor == 0 {
+		return nil
+	}
+	cycle := types.BillingCycle{
+		Interval:      types.BillingInterval(price.Recurring.Interval),
+		IntervalCount: max(price.Recurring.IntervalCount, 1),
+		Anchor:        unixTime(s.BillingCycleAnchor),
+	}
+	if !cycle.Valid() {
+		return nil
+	}
+	return &cycle
+}
+
+// SubscriptionStatus maps the Stripe status onto the shared status set
+func (s *Subscription) SubscriptionStatus() types.SubscriptionStatus {
+	return MapStatus(s.Status)
+}
+
+// CreateRequest maps the subscription onto a create request.
+// The owning user is read from the "user_id" metadata key set at checkout, and an
+// owning organization from "org_id"; organization subscriptions carry their item quantity as seats.
+func (s *Subscription) CreateRequest() (types.SubscriptionCreateRequest, error) {
+	userID := s.Metadata[MetadataUserID]
+	if userID == "" {
+		return types.SubscriptionCreateRequest{}, fmt.Errorf("stripe: subscription %s has no %s metadata", s.ID, MetadataUserID)
+	}
+	orgID, err := s.OrgID()
+	if err != nil {
+		return types.SubscriptionCreateRequest{}, err
+	}
+	req := types.SubscriptionCreateRequest{
+		UserID:               userID,
+		OrgID:                orgID,
+		StripeCustomerID:     s.Customer.ID,
+		StripeSubscriptionID: s.ID,
+		Plan:                 s.Plan(),
+		Tier:                 s.Tier(),
+		Status:               s.SubscriptionStatus(),
+		StartedAt:            unixTime(s.StartDate),
+		CurrentPeriodStart:   unixTime(s.CurrentPeriodStart),
+		CurrentPeriodEnd:     unixTime(s.CurrentPeriodEnd),
+		TrialEndsAt:          unixTimePtr(s.TrialEnd),
+		BillingCycle:         s.BillingCycle(),
+	}
+	if orgID != nil {
+		req.Seats = s.Seats()
+	}
+	return req, nil
+}
+
+// UpdateRequest maps the subscription onto an update request carrying its full current state
+func (s *Subscription) UpdateRequest() types.SubscriptionUpdateRequest {
+	status := s.SubscriptionStatus()
+	active := status == types.SubscriptionStatusActive || status == types.SubscriptionStatusTrialing
+	req := types.SubscriptionUpdateRequest{
+		Status:                &status,
+		HasActiveSubscription: &active,
+		CurrentPeriodStart:    unixTimePtr(s.CurrentPeriodStart),
+		CurrentPeriodEnd:      unixTimePtr(s.CurrentPeriodEnd),
+		CanceledAt:            unixTimePtr(s.CanceledAt),
+		TrialEndsAt:           unixTimePtr(s.TrialEnd),
+		BillingCycle:          s.BillingCycle(),
+	}
+	if s.Customer.ID != "" {
+		customerID := s.Customer.ID
+		req.StripeCustomerID = &customerID
+	}
+	if plan := s.Plan(); plan != "" {
+		req.Plan = &plan
+	}
+	if tier := s.Tier(); tier != "" {
+		req.Tier = &tier
+	}
+	if s.Metadata[MetadataOrgID] != "" {
+		seats := s.Seats()
+		req.Seats = &seats
+	}
+	return req
+}
+
+// MapStatus maps a Stripe subscription status onto the shared status set.
+// Unknown statuses are treated as incomplete.
+func MapStatus(status string) types.SubscriptionStatus {
+	if mapped := types.SubscriptionStatus(status); mapped.Valid() {
+		return mapped
+	}
+	return types.SubscriptionStatusIncomplete
+}
+
+func parseTier(value string) (types.SubscriptionTier, bool) {
+	switch tier := types.SubscriptionTier(value); tier {
+	case types.SubscriptionTierBasic, types.SubscriptionTierPro, types.SubscriptionTierEnterprise:
+		return tier, true
+	}
+	return "", false
+}
+
+func unixTime(seconds int64) time.Time {
+	if seconds == 0 {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0).UTC()
+}
+
+func unixTimePtr(seconds int64) *time.Time {
+	if seconds == 0 {
+		return nil
+	}
+	t := time.Unix(seconds, 0).UTC()
+	return &t
+}
@@ -0,0 +1,47 @@
+package stripe
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+func TestSubscriptionBillingCycle(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    *types.BillingCycle
+	}{
+		{
+			"quarterly",
+			`{"billing_cycle_anchor":1769817600,"items":{"data":[{"price":{"recurring":{"interval":"month","interval_count":3}}}]}}`,
+			&types.BillingCycle{Interval: types.BillingIntervalMonth, IntervalCount: 3, Anchor: time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			"missing interval count",
+			`{"billing_cycle_anchor":1769817600,"items":{"data":[{"price":{"recurring":{"interval":"year"}}}]}}`,
+			&types.BillingCycle{Interval: types.BillingIntervalYear, IntervalCount: 1, Anchor: time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)},
+		},
+		{"no anchor", `{"items":{"data":[{"price":{"recurring":{"interval":"month","interval_count":1}}}]}}`, nil},
+		{"one-off price", `{"billing_cycle_anchor":1769817600,"items":{"data":[{"price":{"unit_amount":500}}]}}`, nil},
+		{"unknown interval", `{"billing_cycle_anchor":1769817600,"items":{"data":[{"price":{"recurring":{"interval":"decade"}}}]}}`, nil},
+		{"no items", `{"billing_cycle_anchor":1769817600}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sub Subscription
+			if err := json.Unmarshal([]byte(tt.payload), &sub); err != nil {
+				t.Fatal(err)
+			}
+			got := sub.BillingCycle()
+			if (got == nil) != (tt.want == nil) || got != nil && *got != *tt.want {
+				t.Errorf("BillingCycle() = %+v, want %+v", got, tt.want)
+			}
+			if req := sub.UpdateRequest(); (req.BillingCycle == nil) != (tt.want == nil) {
+				t.Errorf("UpdateRequest().BillingCycle = %+v", req.BillingCycle)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package stripe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Handler applies a verified, deduplicated event to the service's own state
+type Handler func(ctx context.Context, event *Event) error
+
+// Outcome reports what Process did with an event
+type Outcome int
+
+const (
+	// OutcomeApplied means the handler ran and succeeded
+	OutcomeApplied Outcome = iota
+	// OutcomeDuplicate means the event was already processed
+	OutcomeDuplicate
+	// OutcomeStale means a newer event for the same subscription was already applied
+	OutcomeStale
+	// OutcomeFailed means the handler failed and the event was dead-lettered
+	OutcomeFailed
+)
+
+// String returns the outcome name
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeApplied:
+		return "applied"
+	case OutcomeDuplicate:
+		return "duplicate"
+	case OutcomeStale:
+		return "stale"
+	case OutcomeFailed:
+		return "failed"
+	}
+	return fmt.Sprintf("Outcome(%d)", int(o))
+}
+
+// Processor runs webhook events through a Handler at most once each.
+// Events are deduplicated by ID and ordered by their Position per subscription,
+// so a retried or late delivery never overwrites newer state, even when it was
+// created in the same second.
+// Events whose handler fails are released for Stripe's own retries and kept
+// in the dead-letter store until they are replayed.
+//
+// Events for one subscription are processed one at a time under Locks. The default
+// MemoryLocker only covers a single instance; services running several instances
+// against a shared EventStore need a distributed Locker for the ordering to hold.
+type Processor struct {
+	verifier    *Verifier
+	handler     Handler
+	events      EventStore
+	deadLetters DeadLetterStore
+
+	// Locks serializes processing per subscription; defaults to a MemoryLocker
+	Locks Locker
+	// Now returns the current time; defaults to time.Now
+	Now func() time.Time
+}
+
+// NewProcessor creates a processor that verifies deliveries with verifier and applies them with handler
+func NewProcessor(verifier *Verifier, handler Handler, events EventStore, deadLetters DeadLetterStore) *Processor {
+	return &Processor{
+		verifier:    verifier,
+		handler:     handler,
+		events:      events,
+		deadLetters: deadLetters,
+		Locks:       NewMemoryLocker(),
+	}
+}
+
+// HandleWebhook verifies and processes a delivery, matching SubscriptionServiceClient.HandleStripeWebhook.
+// Duplicate and stale events succeed so Stripe stops retrying them.
+func (p *Processor) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	event, err := p.verifier.ConstructEvent(payload, signature)
+	if err != nil {
+		return err
+	}
+	_, err = p.Process(ctx, event)
+	return err
+}
+
+// Process applies a verified event unless it is a duplicate or stale.
+// A handler failure dead-letters the event and is returned with OutcomeFailed.
+func (p *Processor) Process(ctx context.Context, event *Event) (Outcome, error) {
+	claimed, err := p.events.Claim(ctx, event.ID, p.now())
+	if err != nil {
+		return OutcomeFailed, fmt.Errorf("stripe: claim event: %w", err)
+	}
+	if !claimed {
+		return OutcomeDuplicate, nil
+	}
+
+	subscriptionID := event.SubscriptionID()
+	if subscriptionID != "" {
+		unlock, err := p.Locks.Lock(ctx, subscriptionID)
+		if err != nil {
+			return OutcomeFailed, p.release(ctx, event.ID, fmt.Errorf("stripe: lock subscription: %w", err))
+		}
+		defer unlock()
+
+		last, err := p.events.LastApplied(ctx, subscriptionID)
+		if err != nil {
+			return OutcomeFailed, p.release(ctx, event.ID, fmt.Errorf("stripe: load last applied event: %w", err))
+		}
+		if !last.Before(PositionOf(event)) {
+			return OutcomeStale, nil
+		}
+	}
+
+	if err := p.handler(ctx, event); err != nil {
+		return OutcomeFailed, p.deadLetter(ctx, event, err)
+	}
+
+	if subscriptionID != "" {
+		if err := p.events.Advance(ctx, subscriptionID, PositionOf(event)); err != nil {
+			return OutcomeApplied, fmt.Errorf("stripe: record applied event: %w", err)
+		}
+	}
+	return OutcomeApplied, nil
+}
+
+// Replay processes a dead-lettered event again and removes it unless it fails again
+func (p *Processor) Replay(ctx context.Context, eventID string) (Outcome, error) {
+	letter, err := p.deadLetters.Get(ctx, eventID)
+	if err != nil {
+		return OutcomeFailed, err
+	}
+	return p.replay(ctx, letter)
+}
+
+// ReplayAll replays every dead-lettered event, oldest first, and joins the errors of those that fail again
+func (p *Processor) ReplayAll(ctx context.Context) error {
+	letters, err := p.deadLetters.List(ctx)
+	if err != nil {
+		return fmt.Errorf("stripe: list dead letters: %w", err)
+	}
+	var errs []error
+	for _, letter := range letters {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := p.replay(ctx, letter); err != nil {
+			errs = append(errs, fmt.Errorf("stripe: replay %s: %w", letter.Event.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DeadLetters returns every dead-lettered event, oldest failure first
+func (p *Processor) DeadLetters(ctx context.Context) ([]*DeadLetter, error) {
+	return p.deadLetters.List(ctx)
+}
+
+func (p *Processor) replay(ctx context.Context, letter *DeadLetter) (Outcome, error) {
+	outcome, err := p.Process(ctx, &letter.Event)
+	if err != nil {
+		return outcome, err
+	}
+	if err := p.deadLetters.Delete(ctx, letter.Event.ID); err != nil {
+		return outcome, fmt.Errorf("stripe: delete dead letter: %w", err)
+	}
+	return outcome, nil
+}
+
+func (p *Processor) deadLetter(ctx context.Context, event *Event, cause error) error {
+	attempts := 1
+	existing, err := p.deadLetters.Get(ctx, event.ID)
+	switch {
+	case err == nil:
+		attempts = existing.Attempts + 1
+	case !errors.Is(err, ErrDeadLetterNotFound):
+		return p.release(ctx, event.ID, errors.Join(cause, fmt.Errorf("stripe: load dead letter: %w", err)))
+	}
+
+	letter := &DeadLetter{
+		Event:    *event,
+		Error:    cause.Error(),
+		Attempts: attempts,
+		FailedAt: p.now(),
+	}
+	if err := p.deadLetters.Put(ctx, letter); err != nil {
+		cause = errors.Join(cause, fmt.Errorf("stripe: store dead letter: %w", err))
+	}
+	return p.release(ctx, event.ID, cause)
+}
+
+// release un-claims the event so a retry can process it, and returns cause
+func (p *Processor) release(ctx context.Context, eventID string, cause error) error {
+	if err := p.events.Release(ctx, eventID); err != nil {
+		return errors.Join(cause, fmt.Errorf("stripe: release event: %w", err))
+	}
+	return cause
+}
+
+func (p *Processor) now() time.Time {
+	if p.Now != nil {
+		return p.Now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,236 @@
+package stripe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func subscriptionEvent(t *testing.T, id string, created int64) *Event {
+	t.Helper()
+	payload := fmt.Sprintf(`{"id":%q,"type":"customer.subscription.updated","created":%d,"data":{"object":{"id":"sub_1","status":"active"}}}`, id, created)
+	event, err := ParseEvent([]byte(payload))
+	if err != nil {
+		t.Fatalf("ParseEvent() error = %v", err)
+	}
+	return event
+}
+
+// recorder is a Handler that remembers the events it applied
+type recorder struct {
+	mu      sync.Mutex
+	applied []string
+	fail    map[string]error
+}
+
+func (r *recorder) handle(ctx context.Context, event *Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.fail[event.ID]; err != nil {
+		return err
+	}
+	r.applied = append(r.applied, event.ID)
+	return nil
+}
+
+func newTestProcessor(handler Handler) (*Processor, *MemoryStore) {
+	store := NewMemoryStore()
+	return NewProcessor(NewVerifier(testSecret), handler, store, store), store
+}
+
+func TestProcessDeduplicatesAndOrders(t *testing.T) {
+	ctx := context.Background()
+	rec := &recorder{}
+	p, _ := newTestProcessor(rec.handle)
+
+	steps := []struct {
+		event *Event
+		want  Outcome
+	}{
+		{subscriptionEvent(t, "evt_2", 200), OutcomeApplied},
+		{subscriptionEvent(t, "evt_2", 200), OutcomeDuplicate},
+		{subscriptionEvent(t, "evt_1", 100), OutcomeStale},
+		{subscriptionEvent(t, "evt_3", 200), OutcomeApplied},
+		{subscriptionEvent(t, "evt_4", 300), OutcomeApplied},
+	}
+	for _, step := range steps {
+		got, err := p.Process(ctx, step.event)
+		if err != nil || got != step.want {
+			t.Errorf("Process(%s) = %v, %v, want %v", step.event.ID, got, err, step.want)
+		}
+	}
+	if fmt.Sprint(rec.applied) != "[evt_2 evt_3 evt_4]" {
+		t.Errorf("applied = %v", rec.applied)
+	}
+}
+
+func TestProcessOrdersSameSecondEvents(t *testing.T) {
+	ctx := context.Background()
+
+	// Both delivery orders must leave the same event applied last
+	for _, order := range [][]string{{"evt_a", "evt_b"}, {"evt_b", "evt_a"}} {
+		t.Run(fmt.Sprint(order), func(t *testing.T) {
+			rec := &recorder{}
+			p, store := newTestProcessor(rec.handle)
+
+			for _, id := range order {
+				if _, err := p.Process(ctx, subscriptionEvent(t, id, 100)); err != nil {
+					t.Fatalf("Process(%s) error = %v", id, err)
+				}
+			}
+			if got := rec.applied[len(rec.applied)-1]; got != "evt_b" {
+				t.Errorf("applied = %v, want evt_b last", rec.applied)
+			}
+			if last, _ := store.LastApplied(ctx, "sub_1"); last != (Position{Created: 100, EventID: "evt_b"}) {
+				t.Errorf("LastApplied() = %+v", last)
+			}
+
+			// An older event from the same second is stale once a later one was applied
+			got, err := p.Process(ctx, subscriptionEvent(t, "evt_0", 100))
+			if err != nil || got != OutcomeStale {
+				t.Errorf("Process(evt_0) = %v, %v, want stale", got, err)
+			}
+		})
+	}
+}
+
+func TestPositionBefore(t *testing.T) {
+	tests := []struct {
+		a, b Position
+		want bool
+	}{
+		{Position{}, Position{Created: 1, EventID: "evt_1"}, true},
+		{Position{Created: 1, EventID: "evt_z"}, Position{Created: 2, EventID: "evt_a"}, true},
+		{Position{Created: 2, EventID: "evt_a"}, Position{Created: 1, EventID: "evt_z"}, false},
+		{Position{Created: 1, EventID: "evt_a"}, Position{Created: 1, EventID: "evt_b"}, true},
+		{Position{Created: 1, EventID: "evt_b"}, Position{Created: 1, EventID: "evt_a"}, false},
+		{Position{Created: 1, EventID: "evt_a"}, Position{Created: 1, EventID: "evt_a"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Before(tt.b); got != tt.want {
+			t.Errorf("%+v.Before(%+v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestProcessDeadLettersAndReplays(t *testing.T) {
+	ctx := context.Background()
+	rec := &recorder{fail: map[string]error{"evt_1": errors.New("database down")}}
+	p, _ := newTestProcessor(rec.handle)
+
+	event := subscriptionEvent(t, "evt_1", 100)
+	if got, err := p.Process(ctx, event); got != OutcomeFailed || err == nil {
+		t.Fatalf("Process() = %v, %v, want failure", got, err)
+	}
+	// The event was released, so Stripe's retry fails again and bumps the attempts
+	if got, _ := p.Process(ctx, event); got != OutcomeFailed {
+		t.Fatalf("Process(retry) = %v, want failure", got)
+	}
+
+	letters, err := p.DeadLetters(ctx)
+	if err != nil || len(letters) != 1 || letters[0].Attempts != 2 || letters[0].Error != "database down" {
+		t.Fatalf("DeadLetters() = %+v, %v", letters, err)
+	}
+
+	delete(rec.fail, "evt_1")
+	if got, err := p.Replay(ctx, "evt_1"); got != OutcomeApplied || err != nil {
+		t.Fatalf("Replay() = %v, %v", got, err)
+	}
+	if letters, _ := p.DeadLetters(ctx); len(letters) != 0 {
+		t.Errorf("DeadLetters() after replay = %+v", letters)
+	}
+	if _, err := p.Replay(ctx, "evt_1"); !errors.Is(err, ErrDeadLetterNotFound) {
+		t.Errorf("Replay(replayed) error = %v", err)
+	}
+}
+
+func TestReplayAllKeepsFailures(t *testing.T) {
+	ctx := context.Background()
+	rec := &recorder{fail: map[string]error{"evt_1": errors.New("boom"), "evt_2": errors.New("boom")}}
+	p, _ := newTestProcessor(rec.handle)
+
+	p.Process(ctx, subscriptionEvent(t, "evt_1", 100))
+	p.Process(ctx, subscriptionEvent(t, "evt_2", 200))
+
+	delete(rec.fail, "evt_1")
+	if err := p.ReplayAll(ctx); err == nil {
+		t.Error("ReplayAll() error = nil, want evt_2's failure")
+	}
+	letters, _ := p.DeadLetters(ctx)
+	if len(letters) != 1 || letters[0].Event.ID != "evt_2" {
+		t.Errorf("DeadLetters() = %+v", letters)
+	}
+}
+
+func TestProcessSerializesSubscription(t *testing.T) {
+	ctx := context.Background()
+	release := make(chan struct{})
+	started := make(chan string, 2)
+
+	var mu sync.Mutex
+	state := ""
+	p, _ := newTestProcessor(func(ctx context.Context, event *Event) error {
+		started <- event.ID
+		if event.ID == "evt_old" {
+			<-release
+		}
+		mu.Lock()
+		state = event.ID
+		mu.Unlock()
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		p.Process(ctx, subscriptionEvent(t, "evt_old", 100))
+	}()
+	if id := <-started; id != "evt_old" {
+		t.Fatalf("first handler = %s", id)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		p.Process(ctx, subscriptionEvent(t, "evt_new", 200))
+	}()
+
+	select {
+	case id := <-started:
+		t.Fatalf("handler for %s ran while evt_old was in flight", id)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	wg.Wait()
+	if state != "evt_new" {
+		t.Errorf("final state = %s, want evt_new", state)
+	}
+}
+
+func TestMemoryLockerHonorsContext(t *testing.T) {
+	locker := NewMemoryLocker()
+	unlock, err := locker.Lock(context.Background(), "sub_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := locker.Lock(ctx, "sub_1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Lock(held) error = %v, want deadline exceeded", err)
+	}
+
+	unlock()
+	unlock()
+	next, err := locker.Lock(context.Background(), "sub_1")
+	if err != nil {
+		t.Fatalf("Lock(after unlock) error = %v", err)
+	}
+	next()
+}
@@ -0,0 +1,142 @@
+package stripe
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader is the HTTP header Stripe signs webhook deliveries with
+const SignatureHeader = "Stripe-Signature"
+
+// DefaultTolerance is the maximum age of a signed delivery accepted by default
+const DefaultTolerance = 5 * time.Minute
+
+// Signature verification errors
+var (
+	ErrMissingHeader    = errors.New("stripe: missing signature header")
+	ErrInvalidHeader    = errors.New("stripe: invalid signature header")
+	ErrNoValidSignature = errors.New("stripe: no valid signature for payload")
+	ErrTooOld           = errors.New("stripe: timestamp outside tolerance window")
+)
+
+// Verifier checks Stripe-Signature headers against an endpoint signing secret
+type Verifier struct {
+	secret []byte
+
+	// Tolerance is the accepted clock difference; zero means DefaultTolerance
+	// and a negative value disables the timestamp check
+	Tolerance time.Duration
+	// Now returns the current time; defaults to time.Now
+	Now func() time.Time
+}
+
+// NewVerifier creates a verifier for the endpoint signing secret ("whsec_...")
+func NewVerifier(secret string) *Verifier {
+	return &Verifier{secret: []byte(secret)}
+}
+
+// Verify checks that header carries a valid v1 signature of payload within the tolerance window.
+// Any of several v1 signatures may match, as Stripe sends one per active secret during rotation.
+func (v *Verifier) Verify(payload []byte, header string) error {
+	if header == "" {
+		return ErrMissingHeader
+	}
+
+	timestamp, signatures, err := parseHeader(header)
+	if err != nil {
+		return err
+	}
+
+	expected := computeSignature(v.secret, timestamp, payload)
+	valid := false
+	for _, signature := range signatures {
+		if hmac.Equal(expected, signature) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return ErrNoValidSignature
+	}
+
+	tolerance := v.Tolerance
+	if tolerance == 0 {
+		tolerance = DefaultTolerance
+	}
+	if tolerance > 0 {
+		age := v.now().Sub(time.Unix(timestamp, 0))
+		if age > tolerance || age < -tolerance {
+			return ErrTooOld
+		}
+	}
+	return nil
+}
+
+// ConstructEvent verifies the delivery and parses it into an Event
+func (v *Verifier) ConstructEvent(payload []byte, header string) (*Event, error) {
+	if err := v.Verify(payload, header); err != nil {
+		return nil, err
+	}
+	return ParseEvent(payload)
+}
+
+// SignHeader builds a Stripe-Signature header for payload, for tests and local tooling
+func SignHeader(secret string, payload []byte, at time.Time) string {
+	timestamp := at.Unix()
+	signature := computeSignature([]byte(secret), timestamp, payload)
+	return "t=" + strconv.FormatInt(timestamp, 10) + ",v1=" + hex.EncodeToString(signature)
+}
+
+// parseHeader extracts the timestamp and every v1 signature from a Stripe-Signature header
+func parseHeader(header string) (int64, [][]byte, error) {
+	var (
+		timestamp  int64
+		signatures [][]byte
+		err        error
+	)
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return 0, nil, ErrInvalidHeader
+		}
+		switch key {
+		case "t":
+			if timestamp, err = strconv.ParseInt(value, 10, 64); err != nil {
+				return 0, nil, ErrInvalidHeader
+			}
+		case "v1":
+			signature, err := hex.DecodeString(value)
+			if err != nil {
+				continue
+			}
+			signatures = append(signatures, signature)
+		}
+	}
+	if timestamp == 0 {
+		return 0, nil, ErrInvalidHeader
+	}
+	if len(signatures) == 0 {
+		return 0, nil, ErrNoValidSignature
+	}
+	return timestamp, signatures, nil
+}
+
+func computeSignature(secret []byte, timestamp int64, payload []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+func (v *Verifier) now() time.Time {
+	if v.Now != nil {
+		return v.Now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,86 @@
+package stripe
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+)
+
+const testSecret = "whsec_test"
+
+var testPayload = []byte(`{"id":"evt_1","object":"event","type":"invoice.paid","created":1700000000,"data":{"object":{"id":"in_1","subscription":"sub_1"}}}`)
+
+func testVerifier(now time.Time) *Verifier {
+	v := NewVerifier(testSecret)
+	v.Now = func() time.Time { return now }
+	return v
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	valid := SignHeader(testSecret, testPayload, now)
+	other := SignHeader("whsec_other", testPayload, now)
+	_, otherSig, _ := parseHeader(other)
+	_, validSig, _ := parseHeader(valid)
+	ts := "t=1700000000"
+
+	tests := []struct {
+		name    string
+		header  string
+		payload []byte
+		now     time.Time
+		want    error
+	}{
+		{"valid", valid, testPayload, now, nil},
+		{"second of several v1 signatures", ts + ",v1=" + hex.EncodeToString(otherSig[0]) + ",v1=" + hex.EncodeToString(validSig[0]), testPayload, now, nil},
+		{"v0 and unknown schemes ignored", ts + ",v0=abc,v1=" + hex.EncodeToString(validSig[0]) + ",x=1", testPayload, now, nil},
+		{"wrong secret", other, testPayload, now, ErrNoValidSignature},
+		{"tampered payload", valid, append([]byte(" "), testPayload...), now, ErrNoValidSignature},
+		{"edge of tolerance", valid, testPayload, now.Add(DefaultTolerance), nil},
+		{"past tolerance", valid, testPayload, now.Add(DefaultTolerance + time.Second), ErrTooOld},
+		{"from the future", valid, testPayload, now.Add(-DefaultTolerance - time.Second), ErrTooOld},
+		{"missing header", "", testPayload, now, ErrMissingHeader},
+		{"no timestamp", "v1=" + hex.EncodeToString(validSig[0]), testPayload, now, ErrInvalidHeader},
+		{"bad timestamp", "t=soon,v1=" + hex.EncodeToString(validSig[0]), testPayload, now, ErrInvalidHeader},
+		{"part without value", ts + ",v1", testPayload, now, ErrInvalidHeader},
+		{"no v1 signature", ts + ",v0=abc", testPayload, now, ErrNoValidSignature},
+		{"non-hex signature", ts + ",v1=zz", testPayload, now, ErrNoValidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := testVerifier(tt.now).Verify(tt.payload, tt.header)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyTolerance(t *testing.T) {
+	signedAt := time.Unix(1700000000, 0)
+	header := SignHeader(testSecret, testPayload, signedAt)
+
+	v := testVerifier(signedAt.Add(time.Hour))
+	v.Tolerance = 2 * time.Hour
+	if err := v.Verify(testPayload, header); err != nil {
+		t.Errorf("Verify(custom tolerance) error = %v", err)
+	}
+
+	v.Tolerance = -1
+	v.Now = func() time.Time { return signedAt.Add(365 * 24 * time.Hour) }
+	if err := v.Verify(testPayload, header); err != nil {
+		t.Errorf("Verify(disabled tolerance) error = %v", err)
+	}
+}
+
+func TestConstructEvent(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	event, err := testVerifier(now).ConstructEvent(testPayload, SignHeader(testSecret, testPayload, now))
+	if err != nil {
+		t.Fatalf("ConstructEvent() error = %v", err)
+	}
+	if event.ID != "evt_1" || event.Type != EventInvoicePaid || event.SubscriptionID() != "sub_1" {
+		t.Errorf("ConstructEvent() = %+v", event)
+	}
+}
@@ -0,0 +1,65 @@
+package stripe
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrDeadLetterNotFound is returned for event IDs with no dead letter
+var ErrDeadLetterNotFound = errors.New("stripe: dead letter not found")
+
+// Position orders the events applied to one subscription. Stripe timestamps have one-second
+// resolution, so events created in the same second are ordered by event ID; every instance
+// then agrees on which of them is newest, whatever order they are delivered in.
+type Position struct {
+	Created int64  `json:"created"`
+	EventID string `json:"event_id"`
+}
+
+// PositionOf returns the position of event
+func PositionOf(event *Event) Position {
+	return Position{Created: event.Created, EventID: event.ID}
+}
+
+// Before reports whether p sorts before other
+func (p Position) Before(other Position) bool {
+	if p.Created != other.Created {
+		return p.Created < other.Created
+	}
+	return p.EventID < other.EventID
+}
+
+// EventStore records which events were processed and how far each subscription has been applied.
+// Implementations must make Claim atomic so concurrent deliveries of one event run only once.
+type EventStore interface {
+	// Claim marks the event as processed and reports false if it already was
+	Claim(ctx context.Context, eventID string, at time.Time) (bool, error)
+	// Release forgets a claimed event so a later delivery can process it again
+	Release(ctx context.Context, eventID string) error
+	// LastApplied returns the position of the newest event applied to the subscription,
+	// or the zero Position if none was
+	LastApplied(ctx context.Context, subscriptionID string) (Position, error)
+	// Advance records pos as applied to the subscription unless a newer event already was
+	Advance(ctx context.Context, subscriptionID string, pos Position) error
+}
+
+// DeadLetter is an event whose handler failed, kept for replay
+type DeadLetter struct {
+	Event    Event     `json:"event"`
+	Error    string    `json:"error"`
+	Attempts int       `json:"attempts"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// DeadLetterStore keeps failed events until they are replayed
+type DeadLetterStore interface {
+	// Put stores the dead letter, replacing any existing one for the same event ID
+	Put(ctx context.Context, letter *DeadLetter) error
+	// Get returns the dead letter for the event ID, or ErrDeadLetterNotFound
+	Get(ctx context.Context, eventID string) (*DeadLetter, error)
+	// List returns every dead letter, oldest failure first
+	List(ctx context.Context) ([]*DeadLetter, error)
+	// Delete removes the dead letter for the event ID; deleting a missing one is not an error
+	Delete(ctx context.Context, eventID string) error
+}
@@ -0,0 +1,121 @@
+package twilio
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/jonnyt98/atlas-shared/contracts"
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// Webhook routes served by NewHandler
+const (
+	RouteVoice  = "POST /voice"
+	RouteSMS    = "POST /sms"
+	RouteStatus = "POST /status"
+)
+
+// maxBodyBytes limits the size of webhook form bodies
+const maxBodyBytes = 1 << 20
+
+// emptyResponse is the TwiML document that tells Twilio to do nothing further
+const emptyResponse = `<?xml version="1.0" encoding="UTF-8"?><Response></Response>`
+
+// webhookHandler checks Twilio signatures before handing requests to a webhook implementation
+type webhookHandler struct {
+	validator *Validator
+}
+
+// NewHandler mounts svc on RouteVoice, RouteSMS and RouteStatus.
+// Requests without a valid X-Twilio-Signature are rejected with 403 before svc sees them.
+func NewHandler(svc contracts.PhoneWebhookHandler, validator *Validator) http.Handler {
+	h := &webhookHandler{validator: validator}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc(RouteVoice, h.serve(svc.HandleVoiceWebhook))
+	mux.HandleFunc(RouteSMS, h.serve(svc.HandleSMSWebhook))
+	mux.HandleFunc(RouteStatus, h.serve(svc.HandleStatusWebhook))
+
+	return mux
+}
+
+// NewTwiMLHandler mounts svc like NewHandler, answering voice and SMS webhooks with the TwiML svc returns
+func NewTwiMLHandler(svc contracts.PhoneTwiMLWebhookHandler, validator *Validator) http.Handler {
+	h := &webhookHandler{validator: validator}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc(RouteVoice, h.serveTwiML(svc.HandleVoiceWebhook))
+	mux.HandleFunc(RouteSMS, h.serveTwiML(svc.HandleSMSWebhook))
+	mux.HandleFunc(RouteStatus, h.serve(svc.HandleStatusWebhook))
+
+	return mux
+}
+
+func (h *webhookHandler) serveTwiML(handle func(ctx context.Context, data map[string]string) (contracts.TwiMLDocument, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data, ok := h.verify(w, r)
+		if !ok {
+			return
+		}
+		doc, err := handle(r.Context(), data)
+		if err != nil {
+			writeFailure(w, err)
+			return
+		}
+		if doc == nil {
+			writeTwiML(w, []byte(emptyResponse))
+			return
+		}
+		body, err := doc.TwiML()
+		if err != nil {
+			writeFailure(w, err)
+			return
+		}
+		writeTwiML(w, body)
+	}
+}
+
+func (h *webhookHandler) serve(handle func(ctx context.Context, data map[string]string) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data, ok := h.verify(w, r)
+		if !ok {
+			return
+		}
+		if err := handle(r.Context(), data); err != nil {
+			writeFailure(w, err)
+			return
+		}
+		writeTwiML(w, []byte(emptyResponse))
+	}
+}
+
+// verify parses the form and checks its signature, writing an error response on failure
+func (h *webhookHandler) verify(w http.ResponseWriter, r *http.Request) (map[string]string, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form body", http.StatusBadRequest)
+		return nil, false
+	}
+	if err := h.validator.ValidateRequest(r); err != nil {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return nil, false
+	}
+
+	data := make(map[string]string, len(r.PostForm))
+	for key := range r.PostForm {
+		data[key] = r.PostForm.Get(key)
+	}
+	return data, true
+}
+
+func writeTwiML(w http.ResponseWriter, body []byte) {
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// writeFailure answers with the status of the error code, so Twilio falls back to the configured fallback URL
+func writeFailure(w http.ResponseWriter, err error) {
+	status := types.ToAPIError(err).HTTPStatus()
+	http.Error(w, http.StatusText(status), status)
+}
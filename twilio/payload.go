@@ -0,0 +1,185 @@
+package twilio
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// InboundCall is the voice webhook sent when a call reaches one of our numbers
+type InboundCall struct {
+	CallSid       string
+	AccountSid    string
+	From          string
+	To            string
+	CallStatus    string
+	Direction     string
+	CallerName    string
+	FromCity      string
+	FromState     string
+	FromCountry   string
+	ForwardedFrom string
+}
+
+// MaxMedia is the most attachments Twilio delivers with one inbound MMS
+const MaxMedia = 10
+
+// Media is an attachment of an inbound MMS
+type Media struct {
+	URL         string
+	ContentType string
+}
+
+// InboundMessage is the messaging webhook sent when an SMS or MMS reaches one of our numbers
+type InboundMessage struct {
+	MessageSid          string
+	AccountSid          string
+	MessagingServiceSid string
+	From                string
+	To                  string
+	Body                string
+	NumSegments         int
+	Media               []Media
+}
+
+// StatusCallback reports the progress of an outbound call or message.
+// Exactly one of CallSid and MessageSid is set.
+type StatusCallback struct {
+	AccountSid    string
+	CallSid       string
+	CallStatus    string
+	CallDuration  int
+	MessageSid    string
+	MessageStatus string
+	From          string
+	To            string
+	ErrorCode     string
+	ErrorMessage  string
+}
+
+// IsCall reports whether the callback is about a call rather than a message
+func (s *StatusCallback) IsCall() bool {
+	return s.CallSid != ""
+}
+
+// Failed reports whether the call or message ended without being delivered or answered
+func (s *StatusCallback) Failed() bool {
+	switch s.CallStatus {
+	case "busy", "failed", "no-answer", "canceled":
+		return true
+	}
+	switch s.MessageStatus {
+	case "failed", "undelivered":
+		return true
+	}
+	return s.ErrorCode != ""
+}
+
+// ParseInboundCall decodes voice webhook params
+func ParseInboundCall(data map[string]string) (*InboundCall, error) {
+	if err := require(data, "CallSid", "From", "To"); err != nil {
+		return nil, err
+	}
+	return &InboundCall{
+		CallSid:       data["CallSid"],
+		AccountSid:    data["AccountSid"],
+		From:          data["From"],
+		To:            data["To"],
+		CallStatus:    data["CallStatus"],
+		Direction:     data["Direction"],
+		CallerName:    data["CallerName"],
+		FromCity:      data["FromCity"],
+		FromState:     data["FromState"],
+		FromCountry:   data["FromCountry"],
+		ForwardedFrom: data["ForwardedFrom"],
+	}, nil
+}
+
+// ParseInboundMessage decodes messaging webhook params, including MediaUrlN/MediaContentTypeN attachments.
+// Payloads claiming more than MaxMedia attachments are rejected.
+func ParseInboundMessage(data map[string]string) (*InboundMessage, error) {
+	if err := require(data, "MessageSid", "From", "To"); err != nil {
+		return nil, err
+	}
+	numMedia, err := optionalInt(data, "NumMedia")
+	if err != nil {
+		return nil, err
+	}
+	if numMedia > MaxMedia {
+		return nil, fmt.Errorf("twilio: NumMedia %d is more than %d", numMedia, MaxMedia)
+	}
+	numSegments, err := optionalInt(data, "NumSegments")
+	if err != nil {
+		return nil, err
+	}
+
+	media := make([]Media, 0, numMedia)
+	for i := 0; i < numMedia; i++ {
+		key := "MediaUrl" + strconv.Itoa(i)
+		if data[key] == "" {
+			return nil, fmt.Errorf("twilio: NumMedia is %d but %s is missing", numMedia, key)
+		}
+		media = append(media, Media{
+			URL:         data[key],
+			ContentType: data["MediaContentType"+strconv.Itoa(i)],
+		})
+	}
+
+	return &InboundMessage{
+		MessageSid:          data["MessageSid"],
+		AccountSid:          data["AccountSid"],
+		MessagingServiceSid: data["MessagingServiceSid"],
+		From:                data["From"],
+		To:                  data["To"],
+		Body:                data["Body"],
+		NumSegments:         numSegments,
+		Media:               media,
+	}, nil
+}
+
+// ParseStatusCallback decodes call or message status callback params
+func ParseStatusCallback(data map[string]string) (*StatusCallback, error) {
+	if data["CallSid"] == "" && data["MessageSid"] == "" {
+		return nil, fmt.Errorf("twilio: status callback has neither CallSid nor MessageSid")
+	}
+	duration, err := optionalInt(data, "CallDuration")
+	if err != nil {
+		return nil, err
+	}
+	status := &StatusCallback{
+		AccountSid:    data["AccountSid"],
+		CallSid:       data["CallSid"],
+		CallStatus:    data["CallStatus"],
+		CallDuration:  duration,
+		MessageSid:    data["MessageSid"],
+		MessageStatus: data["MessageStatus"],
+		From:          data["From"],
+		To:            data["To"],
+		ErrorCode:     data["ErrorCode"],
+		ErrorMessage:  data["ErrorMessage"],
+	}
+	if status.MessageStatus == "" {
+		status.MessageStatus = data["SmsStatus"]
+	}
+	return status, nil
+}
+
+func require(data map[string]string, keys ...string) error {
+	for _, key := range keys {
+		if data[key] == "" {
+			return fmt.Errorf("twilio: missing %s", key)
+		}
+	}
+	return nil
+}
+
+func optionalInt(data map[string]string, key string) (int, error) {
+	value := data[key]
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("twilio: invalid %s %q", key, value)
+	}
+	return n, nil
+}
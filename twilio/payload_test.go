@@ -0,0 +1,70 @@
+package twilio
+
+import (
+	"strconv"
+	"testing"
+)
+
+func inboundMessage(numMedia string, attached int) map[string]string {
+	data := map[string]string{
+		"MessageSid": "SM123",
+		"From":       "+14155552671",
+		"To":         "+14155550100",
+		"Body":       "hello",
+		"NumMedia":   numMedia,
+	}
+	for i := 0; i < attached; i++ {
+		data["MediaUrl"+strconv.Itoa(i)] = "https://api.twilio.com/media/" + strconv.Itoa(i)
+		data["MediaContentType"+strconv.Itoa(i)] = "image/jpeg"
+	}
+	return data
+}
+
+func TestParseInboundMessageMedia(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      map[string]string
+		wantMedia int
+		wantErr   bool
+	}{
+		{"no media", inboundMessage("", 0), 0, false},
+		{"zero media", inboundMessage("0", 0), 0, false},
+		{"two attachments", inboundMessage("2", 2), 2, false},
+		{"at the cap", inboundMessage("10", 10), 10, false},
+		{"above the cap", inboundMessage("11", 11), 0, true},
+		{"huge count", inboundMessage("9223372036854775807", 0), 0, true},
+		{"overflowing count", inboundMessage("99999999999999999999", 0), 0, true},
+		{"negative count", inboundMessage("-1", 0), 0, true},
+		{"not a number", inboundMessage("two", 0), 0, true},
+		{"missing attachment", inboundMessage("2", 1), 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := ParseInboundMessage(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseInboundMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(msg.Media) != tt.wantMedia {
+				t.Fatalf("len(Media) = %d, want %d", len(msg.Media), tt.wantMedia)
+			}
+			for i, m := range msg.Media {
+				if m.URL != tt.data["MediaUrl"+strconv.Itoa(i)] || m.ContentType != "image/jpeg" {
+					t.Errorf("Media[%d] = %+v", i, m)
+				}
+			}
+		})
+	}
+}
+
+func TestParseInboundMessageRequiresFields(t *testing.T) {
+	for _, key := range []string{"MessageSid", "From", "To"} {
+		data := inboundMessage("", 0)
+		delete(data, key)
+		if _, err := ParseInboundMessage(data); err == nil {
+			t.Errorf("ParseInboundMessage() without %s error = nil", key)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package twilio
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+)
+
+// VoiceVerb is a TwiML verb allowed at the top level of a voice response
+type VoiceVerb interface {
+	voiceVerb()
+}
+
+// MessagingVerb is a TwiML verb allowed at the top level of a messaging response
+type MessagingVerb interface {
+	messagingVerb()
+}
+
+// DialNoun is a destination nested in Dial
+type DialNoun interface {
+	dialNoun()
+}
+
+// GatherVerb is a verb nested in Gather while it waits for input
+type GatherVerb interface {
+	gatherVerb()
+}
+
+// VoiceResponse is the TwiML document answering a voice webhook
+type VoiceResponse struct {
+	XMLName xml.Name    `xml:"Response"`
+	Verbs   []VoiceVerb `xml:",any"`
+}
+
+// MessagingResponse is the TwiML document answering a messaging webhook
+type MessagingResponse struct {
+	XMLName xml.Name        `xml:"Response"`
+	Verbs   []MessagingVerb `xml:",any"`
+}
+
+// NewVoiceResponse creates a voice response running verbs in order
+func NewVoiceResponse(verbs ...VoiceVerb) *VoiceResponse {
+	return &VoiceResponse{Verbs: verbs}
+}
+
+// NewMessagingResponse creates a messaging response running verbs in order
+func NewMessagingResponse(verbs ...MessagingVerb) *MessagingResponse {
+	return &MessagingResponse{Verbs: verbs}
+}
+
+// Append adds verbs to the end of the response
+func (r *VoiceResponse) Append(verbs ...VoiceVerb) *VoiceResponse {
+	r.Verbs = append(r.Verbs, verbs...)
+	return r
+}
+
+// Append adds verbs to the end of the response
+func (r *MessagingResponse) Append(verbs ...MessagingVerb) *MessagingResponse {
+	r.Verbs = append(r.Verbs, verbs...)
+	return r
+}
+
+// TwiML renders the response as an XML document
+func (r *VoiceResponse) TwiML() ([]byte, error) {
+	return render(r)
+}
+
+// TwiML renders the response as an XML document
+func (r *MessagingResponse) TwiML() ([]byte, error) {
+	return render(r)
+}
+
+// Say reads text aloud
+type Say struct {
+	XMLName  xml.Name `xml:"Say"`
+	Voice    string   `xml:"voice,attr,omitempty"`
+	Language string   `xml:"language,attr,omitempty"`
+	Loop     int      `xml:"loop,attr,omitempty"`
+	Text     string   `xml:",chardata"`
+}
+
+// Play streams an audio file, or sends DTMF digits when Digits is set
+type Play struct {
+	XMLName xml.Name `xml:"Play"`
+	Loop    int      `xml:"loop,attr,omitempty"`
+	Digits  string   `xml:"digits,attr,omitempty"`
+	URL     string   `xml:",chardata"`
+}
+
+// Pause waits silently for Length seconds
+type Pause struct {
+	XMLName xml.Name `xml:"Pause"`
+	Length  int      `xml:"length,attr,omitempty"`
+}
+
+// Dial connects the call to one or more destinations
+type Dial struct {
+	XMLName   xml.Name   `xml:"Dial"`
+	Action    string     `xml:"action,attr,omitempty"`
+	Method    string     `xml:"method,attr,omitempty"`
+	CallerID  string     `xml:"callerId,attr,omitempty"`
+	Timeout   int        `xml:"timeout,attr,omitempty"`
+	TimeLimit int        `xml:"timeLimit,attr,omitempty"`
+	Record    string     `xml:"record,attr,omitempty"`
+	Nouns     []DialNoun `xml:",any"`
+}
+
+// Number dials a phone number
+type Number struct {
+	XMLName    xml.Name `xml:"Number"`
+	SendDigits string   `xml:"sendDigits,attr,omitempty"`
+	URL        string   `xml:"url,attr,omitempty"`
+	Number     string   `xml:",chardata"`
+}
+
+// Client dials a Twilio Client identity
+type Client struct {
+	XMLName  xml.Name `xml:"Client"`
+	URL      string   `xml:"url,attr,omitempty"`
+	Identity string   `xml:",chardata"`
+}
+
+// Conference joins a named conference room
+type Conference struct {
+	XMLName                xml.Name `xml:"Conference"`
+	Muted                  bool     `xml:"muted,attr,omitempty"`
+	StartConferenceOnEnter *bool    `xml:"startConferenceOnEnter,attr,omitempty"`
+	EndConferenceOnExit    bool     `xml:"endConferenceOnExit,attr,omitempty"`
+	WaitURL                string   `xml:"waitUrl,attr,omitempty"`
+	MaxParticipants        int      `xml:"maxParticipants,attr,omitempty"`
+	StatusCallback         string   `xml:"statusCallback,attr,omitempty"`
+	Name                   string   `xml:",chardata"`
+}
+
+// Gather collects digits or speech, prompting with its nested verbs
+type Gather struct {
+	XMLName       xml.Name     `xml:"Gather"`
+	Input         string       `xml:"input,attr,omitempty"`
+	Action        string       `xml:"action,attr,omitempty"`
+	Method        string       `xml:"method,attr,omitempty"`
+	Timeout       int          `xml:"timeout,attr,omitempty"`
+	NumDigits     int          `xml:"numDigits,attr,omitempty"`
+	FinishOnKey   string       `xml:"finishOnKey,attr,omitempty"`
+	SpeechTimeout string       `xml:"speechTimeout,attr,omitempty"`
+	Language      string       `xml:"language,attr,omitempty"`
+	Hints         string       `xml:"hints,attr,omitempty"`
+	Prompts       []GatherVerb `xml:",any"`
+}
+
+// Record records the caller
+type Record struct {
+	XMLName                 xml.Name `xml:"Record"`
+	Action                  string   `xml:"action,attr,omitempty"`
+	Method                  string   `xml:"method,attr,omitempty"`
+	Timeout                 int      `xml:"timeout,attr,omitempty"`
+	FinishOnKey             string   `xml:"finishOnKey,attr,omitempty"`
+	MaxLength               int      `xml:"maxLength,attr,omitempty"`
+	PlayBeep                *bool    `xml:"playBeep,attr,omitempty"`
+	Transcribe              bool     `xml:"transcribe,attr,omitempty"`
+	TranscribeCallback      string   `xml:"transcribeCallback,attr,omitempty"`
+	RecordingStatusCallback string   `xml:"recordingStatusCallback,attr,omitempty"`
+}
+
+// Redirect hands control to the TwiML at URL
+type Redirect struct {
+	XMLName xml.Name `xml:"Redirect"`
+	Method  string   `xml:"method,attr,omitempty"`
+	URL     string   `xml:",chardata"`
+}
+
+// Hangup ends the call
+type Hangup struct {
+	XMLName xml.Name `xml:"Hangup"`
+}
+
+// Message replies with an SMS, or an MMS when MediaURLs is set
+type Message struct {
+	XMLName        xml.Name `xml:"Message"`
+	To             string   `xml:"to,attr,omitempty"`
+	From           string   `xml:"from,attr,omitempty"`
+	Action         string   `xml:"action,attr,omitempty"`
+	Method         string   `xml:"method,attr,omitempty"`
+	StatusCallback string   `xml:"statusCallback,attr,omitempty"`
+	Body           string   `xml:"Body,omitempty"`
+	MediaURLs      []string `xml:"Media"`
+}
+
+func (Say) voiceVerb()      {}
+func (Play) voiceVerb()     {}
+func (Pause) voiceVerb()    {}
+func (Dial) voiceVerb()     {}
+func (Gather) voiceVerb()   {}
+func (Record) voiceVerb()   {}
+func (Redirect) voiceVerb() {}
+func (Hangup) voiceVerb()   {}
+
+func (Message) messagingVerb()  {}
+func (Redirect) messagingVerb() {}
+
+func (Number) dialNoun()     {}
+func (Client) dialNoun()     {}
+func (Conference) dialNoun() {}
+
+func (Say) gatherVerb()   {}
+func (Play) gatherVerb()  {}
+func (Pause) gatherVerb() {}
+
+// render encodes a response with the XML declaration Twilio expects
+func render(response interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	if err := xml.NewEncoder(&buf).Encode(response); err != nil {
+		return nil, fmt.Errorf("twilio: render twiml: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,111 @@
+package twilio
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jonnyt98/atlas-shared/contracts"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestTwiMLGolden(t *testing.T) {
+	noBeep := false
+	startOnEnter := true
+
+	tests := []struct {
+		name string
+		doc  contracts.TwiMLDocument
+	}{
+		{"say", NewVoiceResponse(
+			Say{Text: "Hello & welcome", Voice: "alice", Language: "en-US", Loop: 2},
+		)},
+		{"play", NewVoiceResponse(
+			Play{URL: "https://example.com/hold.mp3", Loop: 3},
+			Play{Digits: "ww1234"},
+		)},
+		{"dial_number", NewVoiceResponse(
+			Dial{CallerID: "+14155550100", Timeout: 20, Action: "/dial-status", Nouns: []DialNoun{
+				Number{Number: "+14155552671", SendDigits: "wwww1928"},
+			}},
+		)},
+		{"dial_client", NewVoiceResponse(
+			Dial{Nouns: []DialNoun{
+				Client{Identity: "agent-7", URL: "/whisper"},
+			}},
+		)},
+		{"dial_conference", NewVoiceResponse(
+			Dial{Record: "record-from-answer", Nouns: []DialNoun{
+				Conference{
+					Name:                   "room-42",
+					StartConferenceOnEnter: &startOnEnter,
+					EndConferenceOnExit:    true,
+					WaitURL:                "https://example.com/wait",
+					MaxParticipants:        10,
+				},
+			}},
+		)},
+		{"gather", NewVoiceResponse(
+			Gather{Input: "dtmf speech", Action: "/menu", NumDigits: 1, Timeout: 5, Hints: "sales, support", Prompts: []GatherVerb{
+				Say{Text: "Press 1 for sales."},
+				Pause{Length: 1},
+				Play{URL: "https://example.com/menu.mp3"},
+			}},
+			Say{Text: "We did not receive any input."},
+		)},
+		{"record", NewVoiceResponse(
+			Say{Text: "Leave a message after the tone."},
+			Record{Action: "/voicemail", MaxLength: 120, FinishOnKey: "#", PlayBeep: &noBeep, Transcribe: true},
+		)},
+		{"redirect", NewVoiceResponse(
+			Redirect{URL: "/next?step=2&lang=en", Method: "POST"},
+		)},
+		{"hangup", NewVoiceResponse(
+			Say{Text: "Goodbye."},
+			Hangup{},
+		)},
+		{"message_media", NewMessagingResponse(
+			Message{Body: "Here is your receipt", StatusCallback: "/sms-status", MediaURLs: []string{
+				"https://example.com/receipt.png",
+				"https://example.com/logo.png",
+			}},
+			Redirect{URL: "/after-reply"},
+		)},
+		{"empty", NewVoiceResponse()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.doc.TwiML()
+			if err != nil {
+				t.Fatalf("TwiML() error = %v", err)
+			}
+			golden := filepath.Join("testdata", tt.name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatalf("write golden: %v", err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("read golden: %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("TwiML() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestAppendKeepsOrder(t *testing.T) {
+	got, err := NewVoiceResponse(Say{Text: "one"}).Append(Pause{Length: 2}, Hangup{}).TwiML()
+	if err != nil {
+		t.Fatalf("TwiML() error = %v", err)
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<Response><Say>one</Say><Pause length="2"></Pause><Hangup></Hangup></Response>`
+	if string(got) != want {
+		t.Errorf("TwiML() = %s, want %s", got, want)
+	}
+}
@@ -40,7 +40,12 @@ type RefreshTokenRequest struct {
 type RefreshToken struct {
 	ID        string    `json:"id"`
 	UserID    string    `json:"user_id"`
+	// Deprecated: store TokenHash instead of the plaintext token value
 	Token     string    `json:"token"`
+	// TokenHash is the lookup key for stores and is never serialized
+	TokenHash string    `json:"-"`
+	FamilyID  string    `json:"family_id"`
+	ReplacedByID *string `json:"replaced_by_id,omitempty"`
 	ExpiresAt time.Time `json:"expires_at"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
@@ -87,10 +92,17 @@ type TokenClaims struct {
 	Email     string `json:"email"`
 	Role      string `json:"role"`
 	TokenType string `json:"token_type"` // "access" or "refresh"
+	TokenID   string `json:"jti,omitempty"`
 	ExpiresAt int64  `json:"exp"`
 	IssuedAt  int64  `json:"iat"`
 }
 
+// Token types carried in TokenClaims.TokenType
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
 // SessionInfo represents current session information
 type SessionInfo struct {
 	UserID    string    `json:"user_id"`
@@ -15,14 +15,34 @@ type APIError struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 	Details string `json:"details,omitempty"`
+
+	// cause is the error an internal APIError was converted from; it is never encoded
+	cause error
+}
+
+// Unwrap returns the error ToAPIError converted into an internal server error, if any,
+// so servers can log it without sending it to clients
+func (e *APIError) Unwrap() error {
+	return e.cause
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	if e.Details != "" {
+		return e.Code + ": " + e.Message + " (" + e.Details + ")"
+	}
+	return e.Code + ": " + e.Message
 }
 
-// Meta represents metadata for paginated responses
+// Meta represents metadata for paginated responses.
+// Offset pagination fills Page, Total and TotalPages; cursor pagination fills the cursors.
 type Meta struct {
-	Page       int `json:"page"`
-	Limit      int `json:"limit"`
-	Total      int `json:"total"`
-	TotalPages int `json:"total_pages"`
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	Total      int    `json:"total"`
+	TotalPages int    `json:"total_pages"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
 }
 
 // HealthResponse represents health check response
@@ -59,6 +79,8 @@ const (
 	ErrorCodeInternalServer  = "internal_server_error"
 	ErrorCodeBadRequest      = "bad_request"
 	ErrorCodeServiceUnavailable = "service_unavailable"
+	// ErrorCodeInvalidTransition rejects a status change of any state machine, such as a subscription or phone number
+	ErrorCodeInvalidTransition = "invalid_status_transition"
 )
 
 // PaginationDefaults
@@ -0,0 +1,319 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// E.164 length bounds, counting the calling code but not the leading +
+const (
+	minE164Digits = 7
+	maxE164Digits = 15
+)
+
+// E164 is a phone number in canonical E.164 form, such as "+14155552671".
+// Values are validated when encoded to or decoded from JSON and when stored or scanned through SQL;
+// the zero value is an absent number.
+type E164 string
+
+// ParseE164 parses user input in international form ("+44 20 7946 0958", "00 44 ...")
+// or national form for defaultRegion ("(415) 555-2671" for "US").
+// Spaces, dots, dashes and parentheses are ignored. An empty defaultRegion means DefaultCountryCode.
+// Invalid input fails with an error matching ErrValidation.
+func ParseE164(input, defaultRegion string) (E164, error) {
+	raw := strings.TrimSpace(input)
+	if raw == "" {
+		return "", invalidPhone(input, "is empty")
+	}
+
+	international := false
+	switch {
+	case strings.HasPrefix(raw, "+"):
+		international = true
+		raw = raw[1:]
+	case strings.HasPrefix(raw, "00"):
+		international = true
+		raw = raw[2:]
+	}
+
+	var digits strings.Builder
+	for _, r := range raw {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", invalidPhone(input, fmt.Sprintf("contains %q", r))
+		}
+	}
+
+	number := digits.String()
+	if !international {
+		if defaultRegion == "" {
+			defaultRegion = DefaultCountryCode
+		}
+		reg, ok := regions[strings.ToUpper(defaultRegion)]
+		if !ok {
+			return "", invalidPhone(input, fmt.Sprintf("region %q is not supported", defaultRegion))
+		}
+		national := number
+		if reg.callingCode == "1" && len(national) == 11 && strings.HasPrefix(national, "1") {
+			national = national[1:]
+		} else if reg.callingCode != "1" && reg.trunkPrefix != "" {
+			national = strings.TrimPrefix(national, reg.trunkPrefix)
+		}
+		number = reg.callingCode + national
+	}
+
+	n := E164("+" + number)
+	if err := n.validate(); err != nil {
+		return "", invalidPhone(input, err.Error())
+	}
+	return n, nil
+}
+
+// MustParseE164 is ParseE164 for constants; it panics on invalid input
+func MustParseE164(input, defaultRegion string) E164 {
+	n, err := ParseE164(input, defaultRegion)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// Valid reports whether the number is well-formed E.164
+func (n E164) Valid() bool {
+	return n.validate() == nil
+}
+
+// CountryCode returns the country calling code, such as "1" or "44"
+func (n E164) CountryCode() string {
+	code, _ := n.split()
+	return code
+}
+
+// NationalNumber returns the digits after the country calling code
+func (n E164) NationalNumber() string {
+	_, national := n.split()
+	return national
+}
+
+// IsNANP reports whether the number belongs to the North American Numbering Plan
+func (n E164) IsNANP() bool {
+	return n.CountryCode() == "1"
+}
+
+// AreaCode returns the NANP area code, or "" for numbers outside the plan
+func (n E164) AreaCode() string {
+	if !n.IsNANP() || !n.Valid() {
+		return ""
+	}
+	return n.NationalNumber()[:3]
+}
+
+// String returns the E.164 form
+func (n E164) String() string {
+	return string(n)
+}
+
+// National formats the number for dialing inside its country, such as "(415) 555-2671" or "02079460958".
+// Outside NANP the digits are not grouped. Invalid numbers format as "".
+func (n E164) National() string {
+	if !n.Valid() {
+		return ""
+	}
+	code, national := n.split()
+	if code == "1" {
+		return "(" + national[:3] + ") " + national[3:6] + "-" + national[6:]
+	}
+	return trunkPrefixes[code] + national
+}
+
+// International formats the number for dialing from abroad, such as "+1 415-555-2671" or "+44 2079460958".
+// Invalid numbers format as "".
+func (n E164) International() string {
+	if !n.Valid() {
+		return ""
+	}
+	code, national := n.split()
+	if code == "1" {
+		return "+1 " + national[:3] + "-" + national[3:6] + "-" + national[6:]
+	}
+	return "+" + code + " " + national
+}
+
+// RFC3966 formats the number as a tel URI, such as "tel:+1-415-555-2671"
+func (n E164) RFC3966() string {
+	if !n.Valid() {
+		return ""
+	}
+	return "tel:" + strings.ReplaceAll(n.International(), " ", "-")
+}
+
+// MarshalJSON encodes the E.164 form, or "" for the zero value.
+// Invalid numbers fail to encode rather than leave the service.
+func (n E164) MarshalJSON() ([]byte, error) {
+	if n != "" {
+		if err := n.validate(); err != nil {
+			return nil, invalidPhone(string(n), err.Error())
+		}
+	}
+	return json.Marshal(string(n))
+}
+
+// UnmarshalJSON accepts canonical E.164 strings and the empty string
+func (n *E164) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return n.set(s)
+}
+
+// Value stores the E.164 form, or NULL for the zero value
+func (n E164) Value() (driver.Value, error) {
+	if n == "" {
+		return nil, nil
+	}
+	if err := n.validate(); err != nil {
+		return nil, invalidPhone(string(n), err.Error())
+	}
+	return string(n), nil
+}
+
+// Scan reads a canonical E.164 string; NULL scans as the zero value
+func (n *E164) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*n = ""
+		return nil
+	case string:
+		return n.set(v)
+	case []byte:
+		return n.set(string(v))
+	}
+	return fmt.Errorf("types: cannot scan %T into E164", src)
+}
+
+func (n *E164) set(s string) error {
+	if s == "" {
+		*n = ""
+		return nil
+	}
+	if err := E164(s).validate(); err != nil {
+		return invalidPhone(s, err.Error())
+	}
+	*n = E164(s)
+	return nil
+}
+
+// split separates the calling code, trying the one, two and three digit codes in turn
+func (n E164) split() (string, string) {
+	digits := strings.TrimPrefix(string(n), "+")
+	for size := 1; size <= 3 && size < len(digits); size++ {
+		if callingCodes[digits[:size]] {
+			return digits[:size], digits[size:]
+		}
+	}
+	return "", digits
+}
+
+func (n E164) validate() error {
+	s := string(n)
+	if !strings.HasPrefix(s, "+") {
+		return fmt.Errorf("must start with +")
+	}
+	digits := s[1:]
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return fmt.Errorf("must contain only digits after +")
+		}
+	}
+	if len(digits) < minE164Digits || len(digits) > maxE164Digits {
+		return fmt.Errorf("must have %d to %d digits", minE164Digits, maxE164Digits)
+	}
+
+	code, national := n.split()
+	if code == "" {
+		return fmt.Errorf("has an unknown country calling code")
+	}
+	if code == "1" {
+		return validateNANP(national)
+	}
+	return nil
+}
+
+// validateNANP checks the NXX-NXX-XXXX rules of the North American Numbering Plan
+func validateNANP(national string) error {
+	if len(national) != 10 {
+		return fmt.Errorf("NANP numbers have 10 digits")
+	}
+	if !ValidNANPAreaCode(national[:3]) {
+		return fmt.Errorf("area code %s is not valid", national[:3])
+	}
+	exchange := national[3:6]
+	if exchange[0] < '2' || exchange[1:] == "11" {
+		return fmt.Errorf("exchange %s is not valid", exchange)
+	}
+	return nil
+}
+
+// ValidNANPAreaCode reports whether code is a well-formed NANP area code:
+// three digits, not starting with 0 or 1, not an N11 service code and not in the reserved N9X range
+func ValidNANPAreaCode(code string) bool {
+	if len(code) != 3 || code[0] < '2' || code[0] > '9' {
+		return false
+	}
+	for _, r := range code[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return code[1] != '9' && code[1:] != "11"
+}
+
+// AreaCode is a NANP area code, such as "415".
+// Values are validated when encoded to or decoded from JSON; the zero value is an absent area code.
+type AreaCode string
+
+// Valid reports whether the area code is a well-formed NANP area code
+func (c AreaCode) Valid() bool {
+	return ValidNANPAreaCode(string(c))
+}
+
+// String returns the three digits
+func (c AreaCode) String() string {
+	return string(c)
+}
+
+// MarshalJSON encodes the area code, or "" for the zero value.
+// Invalid area codes fail to encode rather than leave the service.
+func (c AreaCode) MarshalJSON() ([]byte, error) {
+	if c != "" && !c.Valid() {
+		return nil, invalidAreaCode(string(c))
+	}
+	return json.Marshal(string(c))
+}
+
+// UnmarshalJSON accepts well-formed NANP area codes and the empty string
+func (c *AreaCode) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s != "" && !AreaCode(s).Valid() {
+		return invalidAreaCode(s)
+	}
+	*c = AreaCode(s)
+	return nil
+}
+
+func invalidAreaCode(input string) error {
+	return ErrValidation.WithDetails(fmt.Sprintf("area code %q is not a valid NANP area code", input))
+}
+
+func invalidPhone(input, reason string) error {
+	return ErrValidation.WithDetails(fmt.Sprintf("phone number %q %s", input, reason))
+}
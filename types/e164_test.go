@@ -0,0 +1,164 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseE164(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		region string
+		want   E164
+	}{
+		{"NANP national", "(415) 555-2671", "US", "+14155552671"},
+		{"NANP with trunk 1", "1 415 555 2671", "US", "+14155552671"},
+		{"NANP dotted, default region", "415.555.2671", "", "+14155552671"},
+		{"NANP region is case-insensitive", "604-555-0134", "ca", "+16045550134"},
+		{"international +", "+44 20 7946 0958", "US", "+442079460958"},
+		{"international 00", "0044 20 7946 0958", "US", "+442079460958"},
+		{"00 prefix with spaces", "00 49 30 123456", "", "+4930123456"},
+		{"GB trunk 0", "020 7946 0958", "GB", "+442079460958"},
+		{"DE trunk 0", "030 123456", "DE", "+4930123456"},
+		{"AU trunk 0", "(02) 9374 4000", "AU", "+61293744000"},
+		{"IT keeps its leading 0", "06 1234 5678", "IT", "+390612345678"},
+		{"three digit calling code", "+353 1 234 5678", "", "+35312345678"},
+		{"surrounding spaces", "  +14155552671 ", "", "+14155552671"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseE164(tt.input, tt.region)
+			if err != nil {
+				t.Fatalf("ParseE164(%q, %q) error = %v", tt.input, tt.region, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseE164(%q, %q) = %s, want %s", tt.input, tt.region, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseE164Rejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		region string
+	}{
+		{"empty", "", "US"},
+		{"letters", "415-555-CALL", "US"},
+		{"unsupported region", "020 7946 0958", "XX"},
+		{"too short", "+1234", ""},
+		{"too long", "+4412345678901234", ""},
+		{"unknown calling code", "+999 1234 5678", ""},
+		{"NANP N11 area code", "(911) 555-2671", "US"},
+		{"NANP N9X area code", "(495) 555-2671", "US"},
+		{"NANP area code starting with 0", "(015) 555-2671", "US"},
+		{"NANP area code starting with 1", "+1 115 555 2671", ""},
+		{"NANP exchange starting with 1", "415-155-2671", "US"},
+		{"NANP N11 exchange", "415-911-2671", "US"},
+		{"NANP too short", "415-555-267", "US"},
+		{"NANP too long", "+1 415 555 26710", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseE164(tt.input, tt.region)
+			if !errors.Is(err, ErrValidation) {
+				t.Errorf("ParseE164(%q, %q) = %s, %v, want a validation error", tt.input, tt.region, got, err)
+			}
+		})
+	}
+}
+
+func TestE164Formatting(t *testing.T) {
+	tests := []struct {
+		number        E164
+		country       string
+		national      string
+		international string
+		rfc3966       string
+		areaCode      string
+	}{
+		{"+14155552671", "1", "(415) 555-2671", "+1 415-555-2671", "tel:+1-415-555-2671", "415"},
+		{"+442079460958", "44", "02079460958", "+44 2079460958", "tel:+44-2079460958", ""},
+		{"+390612345678", "39", "0612345678", "+39 0612345678", "tel:+39-0612345678", ""},
+		{"+35312345678", "353", "012345678", "+353 12345678", "tel:+353-12345678", ""},
+		{"not a number", "", "", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.number), func(t *testing.T) {
+			n := tt.number
+			if got := n.CountryCode(); got != tt.country {
+				t.Errorf("CountryCode() = %q, want %q", got, tt.country)
+			}
+			if got := n.National(); got != tt.national {
+				t.Errorf("National() = %q, want %q", got, tt.national)
+			}
+			if got := n.International(); got != tt.international {
+				t.Errorf("International() = %q, want %q", got, tt.international)
+			}
+			if got := n.RFC3966(); got != tt.rfc3966 {
+				t.Errorf("RFC3966() = %q, want %q", got, tt.rfc3966)
+			}
+			if got := n.AreaCode(); got != tt.areaCode {
+				t.Errorf("AreaCode() = %q, want %q", got, tt.areaCode)
+			}
+		})
+	}
+}
+
+func TestE164JSON(t *testing.T) {
+	type payload struct {
+		Number E164 `json:"number"`
+	}
+
+	data, err := json.Marshal(payload{Number: "+14155552671"})
+	if err != nil || string(data) != `{"number":"+14155552671"}` {
+		t.Errorf("Marshal(valid) = %s, %v", data, err)
+	}
+	data, err = json.Marshal(payload{})
+	if err != nil || string(data) != `{"number":""}` {
+		t.Errorf("Marshal(empty) = %s, %v", data, err)
+	}
+	if _, err := json.Marshal(payload{Number: "(415) 555-2671"}); !errors.Is(err, ErrValidation) {
+		t.Errorf("Marshal(invalid) error = %v, want a validation error", err)
+	}
+
+	var got payload
+	if err := json.Unmarshal([]byte(`{"number":"+442079460958"}`), &got); err != nil || got.Number != "+442079460958" {
+		t.Errorf("Unmarshal(valid) = %s, %v", got.Number, err)
+	}
+	if err := json.Unmarshal([]byte(`{"number":"020 7946 0958"}`), &got); !errors.Is(err, ErrValidation) {
+		t.Errorf("Unmarshal(invalid) error = %v, want a validation error", err)
+	}
+	if err := json.Unmarshal([]byte(`{"number":42}`), &got); err == nil {
+		t.Error("Unmarshal(number) error = nil")
+	}
+}
+
+func TestE164SQL(t *testing.T) {
+	if v, err := E164("").Value(); err != nil || v != nil {
+		t.Errorf("Value(empty) = %v, %v, want NULL", v, err)
+	}
+	if v, err := E164("+14155552671").Value(); err != nil || v != "+14155552671" {
+		t.Errorf("Value(valid) = %v, %v", v, err)
+	}
+	if _, err := E164("555-2671").Value(); !errors.Is(err, ErrValidation) {
+		t.Errorf("Value(invalid) error = %v, want a validation error", err)
+	}
+
+	var n E164 = "+14155552671"
+	if err := n.Scan(nil); err != nil || n != "" {
+		t.Errorf("Scan(nil) = %q, %v", n, err)
+	}
+	if err := n.Scan([]byte("+442079460958")); err != nil || n != "+442079460958" {
+		t.Errorf("Scan(bytes) = %q, %v", n, err)
+	}
+	if err := n.Scan("not a number"); !errors.Is(err, ErrValidation) {
+		t.Errorf("Scan(invalid) error = %v, want a validation error", err)
+	}
+	if err := n.Scan(42); err == nil {
+		t.Error("Scan(int) error = nil")
+	}
+}
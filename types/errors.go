@@ -0,0 +1,263 @@
+package types
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// Sentinel errors for the common error codes, for use with errors.Is
+var (
+	ErrValidation         = &APIError{Code: ErrorCodeValidation, Message: "validation failed"}
+	ErrNotFound           = &APIError{Code: ErrorCodeNotFound, Message: "resource not found"}
+	ErrUnauthorized       = &APIError{Code: ErrorCodeUnauthorized, Message: "unauthorized"}
+	ErrForbidden          = &APIError{Code: ErrorCodeForbidden, Message: "forbidden"}
+	ErrConflict           = &APIError{Code: ErrorCodeConflict, Message: "conflict"}
+	ErrInternalServer     = &APIError{Code: ErrorCodeInternalServer, Message: "internal server error"}
+	ErrBadRequest         = &APIError{Code: ErrorCodeBadRequest, Message: "bad request"}
+	ErrServiceUnavailable = &APIError{Code: ErrorCodeServiceUnavailable, Message: "service unavailable"}
+	ErrInvalidTransition  = &APIError{Code: ErrorCodeInvalidTransition, Message: "invalid status transition"}
+)
+
+// Sentinel errors for the auth error codes, for use with errors.Is
+var (
+	ErrInvalidCredentials = &APIError{Code: AuthErrorInvalidCredentials, Message: "invalid credentials"}
+	ErrUserNotFound       = &APIError{Code: AuthErrorUserNotFound, Message: "user not found"}
+	ErrUserAlreadyExists  = &APIError{Code: AuthErrorUserAlreadyExists, Message: "user already exists"}
+	ErrInvalidToken       = &APIError{Code: AuthErrorInvalidToken, Message: "invalid token"}
+	ErrTokenExpired       = &APIError{Code: AuthErrorTokenExpired, Message: "token expired"}
+	ErrEmailNotVerified   = &APIError{Code: AuthErrorEmailNotVerified, Message: "email not verified"}
+	ErrAccountLocked      = &APIError{Code: AuthErrorAccountLocked, Message: "account locked"}
+	ErrInvalidGoogleCode  = &APIError{Code: AuthErrorInvalidGoogleCode, Message: "invalid google code"}
+)
+
+// Sentinel errors for the subscription error codes, for use with errors.Is
+var (
+	ErrQuotaExceeded = &APIError{Code: SubscriptionErrorQuotaExceeded, Message: "subscription quota exceeded"}
+)
+
+// codesMu guards codeStatus and codeParent against RegisterErrorCode
+var codesMu sync.RWMutex
+
+// codeStatus maps error codes to HTTP status codes
+var codeStatus = map[string]int{
+	ErrorCodeValidation:            http.StatusBadRequest,
+	ErrorCodeNotFound:              http.StatusNotFound,
+	ErrorCodeUnauthorized:          http.StatusUnauthorized,
+	ErrorCodeForbidden:             http.StatusForbidden,
+	ErrorCodeConflict:              http.StatusConflict,
+	ErrorCodeInternalServer:        http.StatusInternalServerError,
+	ErrorCodeBadRequest:            http.StatusBadRequest,
+	ErrorCodeServiceUnavailable:    http.StatusServiceUnavailable,
+	ErrorCodeInvalidTransition:     http.StatusConflict,
+	AuthErrorInvalidCredentials:    http.StatusUnauthorized,
+	AuthErrorUserNotFound:          http.StatusNotFound,
+	AuthErrorUserAlreadyExists:     http.StatusConflict,
+	AuthErrorInvalidToken:          http.StatusUnauthorized,
+	AuthErrorTokenExpired:          http.StatusUnauthorized,
+	AuthErrorEmailNotVerified:      http.StatusForbidden,
+	AuthErrorAccountLocked:         http.StatusForbidden,
+	AuthErrorInvalidGoogleCode:     http.StatusBadRequest,
+	SubscriptionErrorQuotaExceeded: http.StatusForbidden,
+}
+
+// codeParent maps specific error codes to the generic code they refine,
+// so that errors.Is(err, ErrUnauthorized) also matches an expired token
+var codeParent = map[string]string{
+	ErrorCodeInvalidTransition:     ErrorCodeConflict,
+	AuthErrorInvalidCredentials:    ErrorCodeUnauthorized,
+	AuthErrorUserNotFound:          ErrorCodeNotFound,
+	AuthErrorUserAlreadyExists:     ErrorCodeConflict,
+	AuthErrorInvalidToken:          ErrorCodeUnauthorized,
+	AuthErrorTokenExpired:          ErrorCodeUnauthorized,
+	AuthErrorEmailNotVerified:      ErrorCodeForbidden,
+	AuthErrorAccountLocked:         ErrorCodeForbidden,
+	AuthErrorInvalidGoogleCode:     ErrorCodeBadRequest,
+	SubscriptionErrorQuotaExceeded: ErrorCodeForbidden,
+}
+
+// RegisterErrorCode registers the HTTP status and optional parent code for a service-specific error code.
+// It is safe to call concurrently with error handling, but codes are best registered from package init functions
+// so every request sees them.
+func RegisterErrorCode(code string, status int, parent string) {
+	codesMu.Lock()
+	defer codesMu.Unlock()
+
+	codeStatus[code] = status
+	if parent != "" {
+		codeParent[code] = parent
+	}
+}
+
+// HTTPStatus returns the HTTP status for an error code, defaulting to 500 for unknown codes
+func HTTPStatus(code string) int {
+	codesMu.RLock()
+	defer codesMu.RUnlock()
+
+	if status, ok := codeStatus[code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// CodeForStatus returns the generic error code for an HTTP status
+func CodeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return ErrorCodeBadRequest
+	case http.StatusUnauthorized:
+		return ErrorCodeUnauthorized
+	case http.StatusForbidden:
+		return ErrorCodeForbidden
+	case http.StatusNotFound:
+		return ErrorCodeNotFound
+	case http.StatusConflict:
+		return ErrorCodeConflict
+	case http.StatusUnprocessableEntity:
+		return ErrorCodeValidation
+	case http.StatusServiceUnavailable:
+		return ErrorCodeServiceUnavailable
+	default:
+		return ErrorCodeInternalServer
+	}
+}
+
+// codeMatches reports whether code equals target or refines it
+func codeMatches(code, target string) bool {
+	codesMu.RLock()
+	defer codesMu.RUnlock()
+
+	for code != "" {
+		if code == target {
+			return true
+		}
+		code = codeParent[code]
+	}
+	return false
+}
+
+// NewAPIError creates an APIError for the given code and message
+func NewAPIError(code, message string) *APIError {
+	return &APIError{Code: code, Message: message}
+}
+
+// WithDetails returns a copy of the error with details attached
+func (e *APIError) WithDetails(details string) *APIError {
+	clone := *e
+	clone.Details = details
+	return &clone
+}
+
+// Is matches errors with the same code, or a generic code this error refines
+func (e *APIError) Is(target error) bool {
+	switch t := target.(type) {
+	case *APIError:
+		return codeMatches(e.Code, t.Code)
+	case *AuthError:
+		return codeMatches(e.Code, t.Code)
+	}
+	return false
+}
+
+// HTTPStatus returns the HTTP status for the error code
+func (e *APIError) HTTPStatus() int {
+	return HTTPStatus(e.Code)
+}
+
+// Response wraps the error in a failed APIResponse envelope
+func (e *APIError) Response() APIResponse {
+	return APIResponse{Success: false, Error: e}
+}
+
+// ErrorResponse converts the error to the legacy ErrorResponse body, keeping its HTTP status in Code.
+// The error code itself is lost; converting back yields the generic code for the status.
+func (e *APIError) ErrorResponse() ErrorResponse {
+	detail := e.Details
+	if detail == "" {
+		detail = e.Message
+	}
+	return ErrorResponse{
+		Error:   detail,
+		Message: e.Message,
+		Code:    e.HTTPStatus(),
+	}
+}
+
+// Error implements the error interface
+func (e *AuthError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// Is matches errors with the same code, or a generic code this error refines
+func (e *AuthError) Is(target error) bool {
+	return e.ToAPIError().Is(target)
+}
+
+// HTTPStatus returns the HTTP status for the error code
+func (e *AuthError) HTTPStatus() int {
+	return HTTPStatus(e.Code)
+}
+
+// ToAPIError converts the AuthError to an APIError with the same code
+func (e *AuthError) ToAPIError() *APIError {
+	return &APIError{Code: e.Code, Message: e.Message}
+}
+
+// ToAPIError converts the ErrorResponse to an APIError.
+// ErrorResponse carries no error code, so the code is the generic one for its HTTP status.
+func (e ErrorResponse) ToAPIError() *APIError {
+	message := e.Message
+	if message == "" {
+		message = e.Error
+	}
+	return &APIError{
+		Code:    CodeForStatus(e.Code),
+		Message: message,
+		Details: e.Error,
+	}
+}
+
+// apiErrorer is implemented by errors that know their APIError representation
+type apiErrorer interface {
+	APIError() *APIError
+}
+
+// ToAPIError converts any error to an APIError.
+// Errors that are not APIError, AuthError or an APIError provider become internal server errors
+// with a fixed message, since their text may hold queries, paths or upstream bodies.
+// The original error stays available to server-side logging through errors.Unwrap.
+func ToAPIError(err error) *APIError {
+	if err == nil {
+		return nil
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	var authErr *AuthError
+	if errors.As(err, &authErr) {
+		return authErr.ToAPIError()
+	}
+	var provider apiErrorer
+	if errors.As(err, &provider) {
+		return provider.APIError()
+	}
+
+	return &APIError{
+		Code:    ErrorCodeInternalServer,
+		Message: ErrInternalServer.Message,
+		cause:   err,
+	}
+}
+
+// ErrorFromResponse returns the error carried by an APIResponse envelope, or nil on success
+func ErrorFromResponse(resp APIResponse) error {
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if !resp.Success {
+		return ErrInternalServer
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestAPIErrorIsMatchesParentCodes(t *testing.T) {
+	err := fmt.Errorf("refresh: %w", ErrTokenExpired.WithDetails("expired at noon"))
+
+	for _, target := range []error{ErrTokenExpired, ErrUnauthorized} {
+		if !errors.Is(err, target) {
+			t.Errorf("errors.Is(err, %v) = false", target)
+		}
+	}
+	if errors.Is(err, ErrInvalidToken) {
+		t.Error("errors.Is(err, ErrInvalidToken) = true")
+	}
+	if got := ToAPIError(err).HTTPStatus(); got != http.StatusUnauthorized {
+		t.Errorf("HTTPStatus() = %d", got)
+	}
+}
+
+func TestRegisterErrorCodeConcurrently(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		code := fmt.Sprintf("test_code_%d", i)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			RegisterErrorCode(code, http.StatusTeapot, ErrorCodeBadRequest)
+		}()
+		go func() {
+			defer wg.Done()
+			_ = HTTPStatus(code)
+			_ = errors.Is(&APIError{Code: code}, ErrBadRequest)
+		}()
+	}
+	wg.Wait()
+
+	err := &APIError{Code: "test_code_3"}
+	if err.HTTPStatus() != http.StatusTeapot || !errors.Is(err, ErrBadRequest) {
+		t.Errorf("registered code: status %d, is bad_request %v", err.HTTPStatus(), errors.Is(err, ErrBadRequest))
+	}
+}
+
+func TestErrorResponseRoundTrip(t *testing.T) {
+	legacy := ErrorResponse{Error: "user 42 does not exist", Message: "resource not found", Code: http.StatusNotFound}
+
+	apiErr := legacy.ToAPIError()
+	if apiErr.Code != ErrorCodeNotFound || apiErr.Message != legacy.Message || apiErr.Details != legacy.Error {
+		t.Fatalf("ToAPIError() = %+v", apiErr)
+	}
+	if got := apiErr.ErrorResponse(); got != legacy {
+		t.Errorf("ErrorResponse() = %+v, want %+v", got, legacy)
+	}
+
+	// Specific codes only survive as the generic code for their status
+	back := ErrTokenExpired.ErrorResponse().ToAPIError()
+	if back.Code != ErrorCodeUnauthorized || back.Message != ErrTokenExpired.Message {
+		t.Errorf("round trip of token_expired = %+v", back)
+	}
+}
+
+func TestToAPIErrorHidesInternalErrors(t *testing.T) {
+	const secret = "pq: relation \"users\" does not exist at /srv/app/db.go:42"
+	raw := errors.New(secret)
+
+	apiErr := ToAPIError(fmt.Errorf("load user: %w", raw))
+	if apiErr.Code != ErrorCodeInternalServer || apiErr.Message != ErrInternalServer.Message || apiErr.Details != "" {
+		t.Errorf("ToAPIError() = %+v, want a bare internal server error", apiErr)
+	}
+	if apiErr.HTTPStatus() != http.StatusInternalServerError {
+		t.Errorf("HTTPStatus() = %d", apiErr.HTTPStatus())
+	}
+
+	for _, v := range []interface{}{apiErr, apiErr.Response(), NewErrorResponse[string](raw)} {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("Marshal(%T) error = %v", v, err)
+		}
+		if strings.Contains(string(data), "relation") || strings.Contains(string(data), "/srv/app") {
+			t.Errorf("Marshal(%T) = %s, leaks the internal error", v, data)
+		}
+	}
+	if strings.Contains(apiErr.Error(), "relation") {
+		t.Errorf("Error() = %q, leaks the internal error", apiErr.Error())
+	}
+
+	// The original error stays reachable for server-side logging
+	if !errors.Is(apiErr, raw) || !errors.Is(apiErr, ErrInternalServer) {
+		t.Errorf("errors.Is() does not match the cause and ErrInternalServer")
+	}
+	if ToAPIError(ErrNotFound).Unwrap() != nil {
+		t.Error("Unwrap() of an APIError that was not converted is not nil")
+	}
+}
@@ -0,0 +1,91 @@
+package types
+
+import "time"
+
+// InvoiceStatus represents valid invoice statuses
+type InvoiceStatus string
+
+const (
+	InvoiceStatusDraft         InvoiceStatus = "draft"
+	InvoiceStatusOpen          InvoiceStatus = "open"
+	InvoiceStatusPaid          InvoiceStatus = "paid"
+	InvoiceStatusUncollectible InvoiceStatus = "uncollectible"
+	InvoiceStatusVoid          InvoiceStatus = "void"
+)
+
+// InvoiceLineType represents what an invoice line charges for
+type InvoiceLineType string
+
+const (
+	InvoiceLineSubscription InvoiceLineType = "subscription"
+	InvoiceLineProration    InvoiceLineType = "proration"
+	InvoiceLineMetered      InvoiceLineType = "metered"
+	InvoiceLineOneOff       InvoiceLineType = "one_off"
+)
+
+// Invoice represents a billing document for a subscription period
+type Invoice struct {
+	ID               string            `json:"id"`
+	UserID           string            `json:"user_id,omitempty"`
+	SubscriptionID   string            `json:"subscription_id,omitempty"`
+	StripeInvoiceID  string            `json:"stripe_invoice_id,omitempty"`
+	Number           string            `json:"number,omitempty"`
+	Status           InvoiceStatus     `json:"status"`
+	Currency         string            `json:"currency"`
+	SubtotalCents    int64             `json:"subtotal_cents"`
+	TaxCents         int64             `json:"tax_cents"`
+	TotalCents       int64             `json:"total_cents"`
+	AmountDueCents   int64             `json:"amount_due_cents"`
+	AmountPaidCents  int64             `json:"amount_paid_cents"`
+	Lines            []InvoiceLineItem `json:"lines"`
+	PeriodStart      time.Time         `json:"period_start"`
+	PeriodEnd        time.Time         `json:"period_end"`
+	HostedInvoiceURL string            `json:"hosted_invoice_url,omitempty"`
+	PDFURL           string            `json:"pdf_url,omitempty"`
+	PaidAt           *time.Time        `json:"paid_at,omitempty"`
+	CreatedAt        time.Time         `json:"created_at"`
+}
+
+// InvoiceLineItem represents a single charge on an invoice.
+// Metered lines carry the usage type they aggregate.
+type InvoiceLineItem struct {
+	ID              string          `json:"id,omitempty"`
+	Type            InvoiceLineType `json:"type"`
+	Description     string          `json:"description"`
+	Quantity        int64           `json:"quantity"`
+	UnitAmountCents int64           `json:"unit_amount_cents,omitempty"`
+	AmountCents     int64           `json:"amount_cents"`
+	Currency        string          `json:"currency"`
+	UsageType       string          `json:"usage_type,omitempty"`
+	PeriodStart     time.Time       `json:"period_start"`
+	PeriodEnd       time.Time       `json:"period_end"`
+}
+
+// PaymentMethod represents a stored card or other payment method
+type PaymentMethod struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Brand     string    `json:"brand,omitempty"`
+	Last4     string    `json:"last4,omitempty"`
+	ExpMonth  int       `json:"exp_month,omitempty"`
+	ExpYear   int       `json:"exp_year,omitempty"`
+	IsDefault bool      `json:"is_default"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// InvoiceListQuery represents query parameters for listing a user's invoices
+type InvoiceListQuery struct {
+	Limit  int           `json:"limit,omitempty"`
+	Cursor string        `json:"cursor,omitempty"`
+	Status InvoiceStatus `json:"status,omitempty" validate:"oneof=draft open paid uncollectible void"`
+}
+
+// PageLimit returns the requested limit clamped to DefaultLimit/MaxLimit
+func (q InvoiceListQuery) PageLimit() int {
+	return NormalizeLimit(q.Limit)
+}
+
+// UpdatePaymentMethodRequest represents the request to change the default payment method
+type UpdatePaymentMethodRequest struct {
+	PaymentMethodID string `json:"payment_method_id" validate:"required"`
+}
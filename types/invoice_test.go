@@ -0,0 +1,79 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestInvoiceJSON(t *testing.T) {
+	start, end := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	invoice := Invoice{
+		ID:            "inv_1",
+		Status:        InvoiceStatusOpen,
+		Currency:      "usd",
+		SubtotalCents: 3003,
+		TotalCents:    3003,
+		Lines: []InvoiceLineItem{
+			{Type: InvoiceLineSubscription, Description: "Pro", Quantity: 1, UnitAmountCents: 3000, AmountCents: 3000, Currency: "usd", PeriodStart: start, PeriodEnd: end},
+			{Type: InvoiceLineMetered, Description: "Outbound SMS", Quantity: 3, AmountCents: 3, Currency: "usd", UsageType: PhoneUsageTypeSMSOutbound, PeriodStart: start, PeriodEnd: end},
+		},
+		PeriodStart: start,
+		PeriodEnd:   end,
+		CreatedAt:   end,
+	}
+	data, err := json.Marshal(invoice)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	// Unpaid invoices have no paid_at, and zero amounts are still reported
+	for _, key := range []string{"paid_at", "number", "pdf_url"} {
+		if _, ok := fields[key]; ok {
+			t.Errorf("%s present in %s", key, data)
+		}
+	}
+	for _, key := range []string{"tax_cents", "amount_paid_cents", "amount_due_cents"} {
+		if string(fields[key]) != "0" {
+			t.Errorf("%s = %s, want 0", key, fields[key])
+		}
+	}
+
+	var lines []map[string]json.RawMessage
+	if err := json.Unmarshal(fields["lines"], &lines); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := lines[1]["unit_amount_cents"]; ok {
+		t.Errorf("metered line has a unit amount: %s", fields["lines"])
+	}
+	if string(lines[1]["usage_type"]) != `"sms_outbound"` || string(lines[0]["unit_amount_cents"]) != "3000" {
+		t.Errorf("lines = %s", fields["lines"])
+	}
+
+	var decoded Invoice
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.TotalCents != 3003 || len(decoded.Lines) != 2 || decoded.Lines[1] != invoice.Lines[1] || !decoded.PeriodEnd.Equal(end) {
+		t.Errorf("round trip = %+v", decoded)
+	}
+}
+
+func TestInvoiceListQueryPageLimit(t *testing.T) {
+	tests := []struct{ limit, want int }{
+		{0, DefaultLimit},
+		{-1, DefaultLimit},
+		{1, 1},
+		{MaxLimit, MaxLimit},
+		{MaxLimit + 1, MaxLimit},
+	}
+	for _, tt := range tests {
+		if got := (InvoiceListQuery{Limit: tt.limit}).PageLimit(); got != tt.want {
+			t.Errorf("PageLimit(%d) = %d, want %d", tt.limit, got, tt.want)
+		}
+	}
+}
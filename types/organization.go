@@ -51,11 +51,18 @@ func (o *Organization) ToResponse() OrganizationResponse {
 	}
 }
 
-// OrganizationListQuery represents query parameters for listing organizations
+// OrganizationListQuery represents query parameters for listing organizations.
+// When Cursor is set, Page is ignored.
 type OrganizationListQuery struct {
-	Page  int    `json:"page,omitempty"`
-	Limit int    `json:"limit,omitempty"`
-	Name  string `json:"name,omitempty"`
+	Page   int    `json:"page,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+	Cursor string `json:"cursor,omitempty"`
+	Name   string `json:"name,omitempty"`
+}
+
+// PageLimit returns the requested limit clamped to DefaultLimit/MaxLimit
+func (q OrganizationListQuery) PageLimit() int {
+	return NormalizeLimit(q.Limit)
 }
 
 // OrganizationListResponse represents the response for organization listing
@@ -65,6 +72,8 @@ type OrganizationListResponse struct {
 	Page          int                    `json:"page"`
 	Limit         int                    `json:"limit"`
 	TotalPages    int                    `json:"total_pages"`
+	NextCursor    string                 `json:"next_cursor,omitempty"`
+	PrevCursor    string                 `json:"prev_cursor,omitempty"`
 }
 
 // AddUserToOrgRequest represents adding a user to an organization
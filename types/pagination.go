@@ -0,0 +1,46 @@
+package types
+
+// Page represents one page of a cursor-paginated listing
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	Limit      int    `json:"limit"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// Meta returns the page's pagination metadata for an APIResponse
+func (p Page[T]) Meta() *Meta {
+	return &Meta{
+		Limit:      p.Limit,
+		NextCursor: p.NextCursor,
+		PrevCursor: p.PrevCursor,
+	}
+}
+
+// NormalizeLimit applies DefaultLimit to unset limits and caps them at MaxLimit
+func NormalizeLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultLimit
+	}
+	if limit > MaxLimit {
+		return MaxLimit
+	}
+	return limit
+}
+
+// NormalizePage applies DefaultPage to unset page numbers
+func NormalizePage(page int) int {
+	if page <= 0 {
+		return DefaultPage
+	}
+	return page
+}
+
+// TotalPages returns the number of pages needed for total items at the given limit
+func TotalPages(total, limit int) int {
+	if limit <= 0 || total <= 0 {
+		return 0
+	}
+	return (total + limit - 1) / limit
+}
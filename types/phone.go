@@ -1,7 +1,11 @@
 package types
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -9,14 +13,16 @@ import (
 type PhoneNumber struct {
 	ID            uuid.UUID              `json:"id"`
 	UserID        string                 `json:"user_id"`
-	Number        string                 `json:"number"`
+	Number        E164                   `json:"number"`
 	TwilioSID     string                 `json:"twilio_sid"`
-	Status        string                 `json:"status"`
+	Status        PhoneNumberStatus      `json:"status"`
 	Capabilities  []string               `json:"capabilities"`
-	AreaCode      string                 `json:"area_code,omitempty"`
+	AreaCode      AreaCode               `json:"area_code,omitempty"`
 	Configuration map[string]interface{} `json:"configuration,omitempty"`
-	CreatedAt     time.Time              `json:"created_at"`
-	UpdatedAt     time.Time              `json:"updated_at"`
+	// QuarantineEndsAt is when a quarantined number goes back to the provider
+	QuarantineEndsAt *time.Time `json:"quarantine_ends_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
 }
 
 // PhoneUsage represents phone usage analytics
@@ -24,8 +30,12 @@ type PhoneUsage struct {
 	ID              uuid.UUID              `json:"id"`
 	PhoneNumberID   uuid.UUID              `json:"phone_number_id"`
 	UsageType       string                 `json:"usage_type"`
-	FromNumber      string                 `json:"from_number,omitempty"`
-	ToNumber        string                 `json:"to_number,omitempty"`
+	// FromNumber and ToNumber are set for parties with a phone number; short codes,
+	// sender IDs, client identities and withheld numbers are in FromParty and ToParty instead
+	FromNumber      E164                   `json:"from_number,omitempty"`
+	ToNumber        E164                   `json:"to_number,omitempty"`
+	FromParty       *UsageParty            `json:"from_party,omitempty"`
+	ToParty         *UsageParty            `json:"to_party,omitempty"`
 	DurationSeconds int                    `json:"duration_seconds,omitempty"`
 	CostCents       int                    `json:"cost_cents,omitempty"`
 	TwilioSID       string                 `json:"twilio_sid,omitempty"`
@@ -34,23 +44,111 @@ type PhoneUsage struct {
 	CreatedAt       time.Time              `json:"created_at"`
 }
 
+// UsagePartyKind is the kind of call or message party that has no E.164 number
+type UsagePartyKind string
+
+// UsagePartyKind constants
+const (
+	// UsagePartyShortCode is a 3 to 8 digit SMS short code
+	UsagePartyShortCode UsagePartyKind = "short_code"
+	// UsagePartySenderID is an alphanumeric SMS sender ID of up to 11 characters
+	UsagePartySenderID UsagePartyKind = "sender_id"
+	// UsagePartyClient is a client identity calling from an app rather than a phone
+	UsagePartyClient UsagePartyKind = "client"
+	// UsagePartyAnonymous is a caller who withheld their number; it has no ID
+	UsagePartyAnonymous UsagePartyKind = "anonymous"
+)
+
+// UsageParty is a call or message party that has no E.164 number
+type UsageParty struct {
+	Kind UsagePartyKind `json:"kind"`
+	ID   string         `json:"id,omitempty"`
+}
+
+// ParseUsageParty parses a provider's From or To value, such as "+14155552671", "12345",
+// "ACME", "client:alice" or "anonymous". Phone numbers must be in international form and
+// are returned as E164; anything else is returned as a UsageParty. Empty input returns neither.
+// Unrecognized values fail with an error matching ErrValidation.
+func ParseUsageParty(raw string) (E164, *UsageParty, error) {
+	raw = strings.TrimSpace(raw)
+	switch {
+	case raw == "":
+		return "", nil, nil
+	case strings.HasPrefix(raw, "+"):
+		n, err := ParseE164(raw, "")
+		return n, nil, err
+	case strings.HasPrefix(raw, "client:"):
+		party := &UsageParty{Kind: UsagePartyClient, ID: strings.TrimPrefix(raw, "client:")}
+		if !party.Valid() {
+			return "", nil, invalidUsageParty(raw)
+		}
+		return "", party, nil
+	}
+	switch strings.ToLower(raw) {
+	case "anonymous", "restricted", "unknown", "private":
+		return "", &UsageParty{Kind: UsagePartyAnonymous}, nil
+	}
+	for _, kind := range []UsagePartyKind{UsagePartyShortCode, UsagePartySenderID} {
+		if party := (UsageParty{Kind: kind, ID: raw}); party.Valid() {
+			return "", &party, nil
+		}
+	}
+	return "", nil, invalidUsageParty(raw)
+}
+
+// Valid reports whether the ID is well-formed for the party's kind
+func (p UsageParty) Valid() bool {
+	switch p.Kind {
+	case UsagePartyShortCode:
+		return len(p.ID) >= 3 && len(p.ID) <= 8 && strings.Trim(p.ID, "0123456789") == ""
+	case UsagePartySenderID:
+		// Sender IDs need a letter, so they cannot be mistaken for numbers
+		return len(p.ID) >= 1 && len(p.ID) <= 11 &&
+			strings.Trim(p.ID, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789 ") == "" &&
+			strings.ContainsAny(p.ID, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	case UsagePartyClient:
+		return p.ID != "" && !strings.ContainsAny(p.ID, " \t\n")
+	case UsagePartyAnonymous:
+		return p.ID == ""
+	}
+	return false
+}
+
+// UnmarshalJSON accepts only valid parties
+func (p *UsageParty) UnmarshalJSON(data []byte) error {
+	type plain UsageParty
+	var party plain
+	if err := json.Unmarshal(data, &party); err != nil {
+		return err
+	}
+	if !UsageParty(party).Valid() {
+		return ErrValidation.WithDetails(fmt.Sprintf("usage party %s %q is not valid", party.Kind, party.ID))
+	}
+	*p = UsageParty(party)
+	return nil
+}
+
+func invalidUsageParty(input string) error {
+	return ErrValidation.WithDetails(fmt.Sprintf("usage party %q is not a phone number, short code, sender ID, client or anonymous", input))
+}
+
 // PhoneProvisionRequest represents a request to provision a new phone number
 type PhoneProvisionRequest struct {
-	UserID       string   `json:"user_id" binding:"required"`
-	AreaCode     string   `json:"area_code,omitempty"`
-	Capabilities []string `json:"capabilities" binding:"required"`
+	UserID       string   `json:"user_id" binding:"required" validate:"required"`
+	AreaCode     AreaCode `json:"area_code,omitempty"`
+	Capabilities []string `json:"capabilities" binding:"required" validate:"required"`
 }
 
 // PhoneProvisionResponse represents the response after provisioning a phone number
 type PhoneProvisionResponse struct {
-	ID           uuid.UUID `json:"id"`
-	UserID       string    `json:"user_id"`
-	Number       string    `json:"number"`
-	TwilioSID    string    `json:"twilio_sid"`
-	Status       string    `json:"status"`
-	Capabilities []string  `json:"capabilities"`
-	AreaCode     string    `json:"area_code,omitempty"`
-	CreatedAt    time.Time `json:"created_at"`
+	ID           uuid.UUID         `json:"id"`
+	UserID       string            `json:"user_id"`
+	Number       E164              `json:"number"`
+	TwilioSID    string            `json:"twilio_sid"`
+	Status       PhoneNumberStatus `json:"status"`
+	Capabilities []string          `json:"capabilities"`
+	AreaCode     AreaCode          `json:"area_code,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
 }
 
 // PhoneNumberListResponse represents a list of phone numbers
@@ -63,14 +161,39 @@ type PhoneUsageListResponse struct {
 	Usage []PhoneUsage `json:"usage"`
 }
 
+// PhoneNumberStatus is the lifecycle state of a phone number
+type PhoneNumberStatus string
+
 // PhoneNumberStatus constants
 const (
-	PhoneNumberStatusActive    = "active"
-	PhoneNumberStatusInactive  = "inactive"
-	PhoneNumberStatusSuspended = "suspended"
-	PhoneNumberStatusReleased  = "released"
+	PhoneNumberStatusActive    PhoneNumberStatus = "active"
+	PhoneNumberStatusInactive  PhoneNumberStatus = "inactive"
+	PhoneNumberStatusSuspended PhoneNumberStatus = "suspended"
+	// PhoneNumberStatusQuarantined numbers are released by the user but still held,
+	// so they can be reclaimed until QuarantineEndsAt
+	PhoneNumberStatusQuarantined PhoneNumberStatus = "quarantined"
+	PhoneNumberStatusReleased    PhoneNumberStatus = "released"
 )
 
+// Valid reports whether s is a known phone number status
+func (s PhoneNumberStatus) Valid() bool {
+	switch s {
+	case PhoneNumberStatusPurchased, PhoneNumberStatusActive, PhoneNumberStatusInactive,
+		PhoneNumberStatusSuspended, PhoneNumberStatusQuarantined, PhoneNumberStatusReleased:
+		return true
+	}
+	return false
+}
+
+// PhoneNumberStatusChange is one entry in the status history of a phone number
+type PhoneNumberStatusChange struct {
+	PhoneNumberID uuid.UUID         `json:"phone_number_id"`
+	From          PhoneNumberStatus `json:"from"`
+	To            PhoneNumberStatus `json:"to"`
+	Reason        string            `json:"reason,omitempty"`
+	At            time.Time         `json:"at"`
+}
+
 // PhoneUsageType constants
 const (
 	PhoneUsageTypeVoiceInbound  = "voice_inbound"
@@ -88,17 +211,93 @@ const (
 	PhoneCapabilityMMS   = "mms"
 )
 
-// PurchasePhoneNumberRequest represents a request to purchase a new phone number (simplified API)
+// PurchasePhoneNumberRequest represents a request to purchase a new phone number (simplified API).
+// With a ReservationID the reserved number is bought; otherwise the provider picks one in the area code.
 type PurchasePhoneNumberRequest struct {
-	AreaCode    string `json:"area_code,omitempty"`
-	CountryCode string `json:"country_code,omitempty"`
+	AreaCode      string     `json:"area_code,omitempty"`
+	CountryCode   string     `json:"country_code,omitempty"`
+	ReservationID *uuid.UUID `json:"reservation_id,omitempty"`
+}
+
+// PhoneNumberType is the kind of number offered by the provider
+type PhoneNumberType string
+
+// PhoneNumberType constants
+const (
+	PhoneNumberTypeLocal    PhoneNumberType = "local"
+	PhoneNumberTypeTollFree PhoneNumberType = "toll_free"
+	PhoneNumberTypeMobile   PhoneNumberType = "mobile"
+)
+
+// AvailableNumberSearchRequest filters the numbers a provider has for sale.
+// Contains and StartsWith take digits, with * matching any single digit.
+// Limit is not validated; providers read it through SearchLimit, which clamps it like list query limits.
+type AvailableNumberSearchRequest struct {
+	CountryCode  string          `json:"country_code,omitempty"`
+	Type         PhoneNumberType `json:"type,omitempty" validate:"oneof=local toll_free mobile"`
+	AreaCode     string          `json:"area_code,omitempty"`
+	Contains     string          `json:"contains,omitempty"`
+	StartsWith   string          `json:"starts_with,omitempty"`
+	Locality     string          `json:"locality,omitempty"`
+	Region       string          `json:"region,omitempty"`
+	Capabilities []string        `json:"capabilities,omitempty"`
+	Limit        int             `json:"limit,omitempty"`
+}
+
+// SearchLimit returns the requested limit clamped to DefaultLimit/MaxLimit
+func (r AvailableNumberSearchRequest) SearchLimit() int {
+	return NormalizeLimit(r.Limit)
+}
+
+// SearchType returns the requested number type, defaulting to local
+func (r AvailableNumberSearchRequest) SearchType() PhoneNumberType {
+	if r.Type == "" {
+		return PhoneNumberTypeLocal
+	}
+	return r.Type
+}
+
+// AvailablePhoneNumber is a number the provider has for sale
+type AvailablePhoneNumber struct {
+	PhoneNumber  E164            `json:"phone_number"`
+	FriendlyName string          `json:"friendly_name,omitempty"`
+	Type         PhoneNumberType `json:"type"`
+	Locality     string          `json:"locality,omitempty"`
+	Region       string          `json:"region,omitempty"`
+	PostalCode   string          `json:"postal_code,omitempty"`
+	CountryCode  string          `json:"country_code"`
+	Capabilities []string        `json:"capabilities"`
+}
+
+// AvailableNumberSearchResponse represents the numbers matching a search
+type AvailableNumberSearchResponse struct {
+	Numbers []AvailablePhoneNumber `json:"numbers"`
+}
+
+// ReservePhoneNumberRequest holds a number from search results while the user confirms the purchase
+type ReservePhoneNumberRequest struct {
+	UserID      string `json:"user_id" validate:"required"`
+	PhoneNumber E164   `json:"phone_number" validate:"required"`
+}
+
+// PhoneNumberReservation is a number held for one user until ExpiresAt
+type PhoneNumberReservation struct {
+	ID          uuid.UUID `json:"id"`
+	UserID      string    `json:"user_id"`
+	PhoneNumber E164      `json:"phone_number"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// Expired reports whether the reservation can no longer be purchased at the given time
+func (r *PhoneNumberReservation) Expired(at time.Time) bool {
+	return !at.Before(r.ExpiresAt)
 }
 
 // PurchasePhoneNumberResponse represents the response after purchasing a phone number (simplified API)
 type PurchasePhoneNumberResponse struct {
-	PhoneNumber string `json:"phone_number"`
-	Sid         string `json:"sid"`
-	Status      string `json:"status"`
+	PhoneNumber E164              `json:"phone_number"`
+	Sid         string            `json:"sid"`
+	Status      PhoneNumberStatus `json:"status"`
 }
 
 // PhoneUsageAnalytics represents aggregated phone usage analytics
@@ -117,6 +316,6 @@ type PhoneUsageAnalytics struct {
 
 // Phone service status constants
 const (
-	PhoneNumberStatusPurchased = "purchased"
-	DefaultCountryCode        = "US"
+	PhoneNumberStatusPurchased PhoneNumberStatus = "purchased"
+	DefaultCountryCode                           = "US"
 )
\ No newline at end of file
@@ -0,0 +1,169 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestAvailableNumberSearchDefaults(t *testing.T) {
+	tests := []struct {
+		limit int
+		want  int
+	}{
+		{0, DefaultLimit},
+		{-5, DefaultLimit},
+		{30, 30},
+		{MaxLimit, MaxLimit},
+		{MaxLimit + 1, MaxLimit},
+	}
+	for _, tt := range tests {
+		if got := (AvailableNumberSearchRequest{Limit: tt.limit}).SearchLimit(); got != tt.want {
+			t.Errorf("SearchLimit() with Limit %d = %d, want %d", tt.limit, got, tt.want)
+		}
+	}
+
+	if got := (AvailableNumberSearchRequest{}).SearchType(); got != PhoneNumberTypeLocal {
+		t.Errorf("SearchType() = %s, want local", got)
+	}
+	if got := (AvailableNumberSearchRequest{Type: PhoneNumberTypeTollFree}).SearchType(); got != PhoneNumberTypeTollFree {
+		t.Errorf("SearchType() = %s, want toll_free", got)
+	}
+}
+
+func TestPhoneNumberReservation(t *testing.T) {
+	expiresAt := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	reservation := PhoneNumberReservation{ID: uuid.New(), UserID: "u1", PhoneNumber: "+14155552671", ExpiresAt: expiresAt}
+
+	if reservation.Expired(expiresAt.Add(-time.Second)) {
+		t.Error("Expired() = true before ExpiresAt")
+	}
+	if !reservation.Expired(expiresAt) {
+		t.Error("Expired() = false at ExpiresAt")
+	}
+
+	data, err := json.Marshal(PurchasePhoneNumberRequest{ReservationID: &reservation.ID})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var req PurchasePhoneNumberRequest
+	if err := json.Unmarshal(data, &req); err != nil || req.ReservationID == nil || *req.ReservationID != reservation.ID {
+		t.Errorf("round trip = %+v, %v", req, err)
+	}
+
+	data, err = json.Marshal(PurchasePhoneNumberRequest{AreaCode: "415"})
+	if err != nil || string(data) != `{"area_code":"415"}` {
+		t.Errorf("Marshal() without reservation = %s, %v", data, err)
+	}
+	if err := json.Unmarshal([]byte(`{"reservation_id":"res_1"}`), &req); err == nil {
+		t.Error("Unmarshal() accepted a reservation ID that is not a UUID")
+	}
+}
+
+func TestParseUsageParty(t *testing.T) {
+	tests := []struct {
+		raw        string
+		wantNumber E164
+		wantParty  *UsageParty
+		wantErr    bool
+	}{
+		{"", "", nil, false},
+		{"+14155552671", "+14155552671", nil, false},
+		{"+44 20 7946 0958", "+442079460958", nil, false},
+		{"12345", "", &UsageParty{Kind: UsagePartyShortCode, ID: "12345"}, false},
+		{"ACME", "", &UsageParty{Kind: UsagePartySenderID, ID: "ACME"}, false},
+		{"Atlas 2FA", "", &UsageParty{Kind: UsagePartySenderID, ID: "Atlas 2FA"}, false},
+		{"client:alice", "", &UsageParty{Kind: UsagePartyClient, ID: "alice"}, false},
+		{"anonymous", "", &UsageParty{Kind: UsagePartyAnonymous}, false},
+		{"Restricted", "", &UsageParty{Kind: UsagePartyAnonymous}, false},
+		{"+1911", "", nil, true},
+		{"+14151552671", "", nil, true},
+		{"4155552671", "", nil, true},
+		{"12", "", nil, true},
+		{"client:", "", nil, true},
+		{"sender-id", "", nil, true},
+		{"TooLongSenderID", "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			number, party, err := ParseUsageParty(tt.raw)
+			if tt.wantErr {
+				if !errors.Is(err, ErrValidation) {
+					t.Errorf("ParseUsageParty() error = %v, want a validation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseUsageParty() error = %v", err)
+			}
+			if number != tt.wantNumber || (party == nil) != (tt.wantParty == nil) || party != nil && *party != *tt.wantParty {
+				t.Errorf("ParseUsageParty() = %q, %+v, want %q, %+v", number, party, tt.wantNumber, tt.wantParty)
+			}
+		})
+	}
+}
+
+func TestPhoneUsageJSON(t *testing.T) {
+	usage := PhoneUsage{
+		UsageType: PhoneUsageTypeSMSInbound,
+		FromParty: &UsageParty{Kind: UsagePartyShortCode, ID: "12345"},
+		ToNumber:  "+14155552671",
+		Status:    "received",
+		CreatedAt: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+	data, err := json.Marshal(usage)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var decoded PhoneUsage
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal(%s) error = %v", data, err)
+	}
+	if decoded.FromNumber != "" || decoded.FromParty == nil || *decoded.FromParty != *usage.FromParty || decoded.ToNumber != usage.ToNumber || decoded.ToParty != nil {
+		t.Errorf("round trip = %+v", decoded)
+	}
+
+	for _, payload := range []string{
+		`{"from_number":"12345"}`,
+		`{"to_number":"anonymous"}`,
+		`{"from_party":{"kind":"short_code","id":"+14155552671"}}`,
+		`{"from_party":{"kind":"anonymous","id":"alice"}}`,
+		`{"to_party":{"kind":"pager","id":"1"}}`,
+	} {
+		if err := json.Unmarshal([]byte(payload), &decoded); !errors.Is(err, ErrValidation) {
+			t.Errorf("Unmarshal(%s) error = %v, want a validation error", payload, err)
+		}
+	}
+	if _, err := json.Marshal(PhoneUsage{FromNumber: "client:alice"}); err == nil {
+		t.Error("Marshal() accepted a client identity as a number")
+	}
+}
+
+func TestAreaCodeJSON(t *testing.T) {
+	data, err := json.Marshal(PhoneProvisionRequest{UserID: "u1", AreaCode: "415"})
+	if err != nil || string(data) != `{"user_id":"u1","area_code":"415","capabilities":null}` {
+		t.Errorf("Marshal() = %s, %v", data, err)
+	}
+	var req PhoneProvisionRequest
+	if err := json.Unmarshal([]byte(`{"user_id":"u1","area_code":"212"}`), &req); err != nil || req.AreaCode != "212" {
+		t.Errorf("Unmarshal() = %+v, %v", req, err)
+	}
+
+	for _, code := range []string{"911", "155", "095", "41", "4155", "a15", "292"} {
+		if AreaCode(code).Valid() {
+			t.Errorf("AreaCode(%q).Valid() = true", code)
+		}
+		if err := json.Unmarshal([]byte(`{"area_code":"`+code+`"}`), &req); !errors.Is(err, ErrValidation) {
+			t.Errorf("Unmarshal(area code %q) error = %v, want a validation error", code, err)
+		}
+	}
+	if _, err := json.Marshal(PhoneNumber{AreaCode: "911"}); err == nil {
+		t.Error("Marshal() accepted an invalid area code")
+	}
+	if _, err := json.Marshal(PhoneProvisionResponse{AreaCode: "000"}); err == nil {
+		t.Error("Marshal() accepted an invalid area code")
+	}
+}
@@ -0,0 +1,73 @@
+package types
+
+import "time"
+
+// CouponDuration controls how long a coupon discounts a subscription
+type CouponDuration string
+
+const (
+	CouponDurationOnce      CouponDuration = "once"
+	CouponDurationRepeating CouponDuration = "repeating"
+	CouponDurationForever   CouponDuration = "forever"
+)
+
+// Coupon represents a reusable discount.
+// Exactly one of PercentOff and AmountOffCents is set; AmountOffCents applies in Currency.
+type Coupon struct {
+	ID               string         `json:"id"`
+	Name             string         `json:"name,omitempty"`
+	PercentOff       float64        `json:"percent_off,omitempty"`
+	AmountOffCents   int64          `json:"amount_off_cents,omitempty"`
+	Currency         string         `json:"currency,omitempty"`
+	Duration         CouponDuration `json:"duration"`
+	DurationInMonths int            `json:"duration_in_months,omitempty"`
+}
+
+// PromotionCode represents a customer-facing code that redeems a coupon
+type PromotionCode struct {
+	ID             string             `json:"id"`
+	Code           string             `json:"code"`
+	Coupon         Coupon             `json:"coupon"`
+	Active         bool               `json:"active"`
+	ExpiresAt      *time.Time         `json:"expires_at,omitempty"`
+	MaxRedemptions int                `json:"max_redemptions,omitempty"`
+	TimesRedeemed  int                `json:"times_redeemed"`
+	Tiers          []SubscriptionTier `json:"tiers,omitempty"`
+}
+
+// Discount represents a coupon applied to a subscription
+type Discount struct {
+	Coupon        Coupon     `json:"coupon"`
+	PromotionCode string     `json:"promotion_code,omitempty"`
+	StartedAt     time.Time  `json:"started_at"`
+	EndsAt        *time.Time `json:"ends_at,omitempty"`
+}
+
+// Reasons a promotion code is rejected
+const (
+	PromotionCodeNotFound        = "not_found"
+	PromotionCodeInactive        = "inactive"
+	PromotionCodeExpired         = "expired"
+	PromotionCodeFullyRedeemed   = "fully_redeemed"
+	PromotionCodeTierNotEligible = "tier_not_eligible"
+)
+
+// ValidatePromotionCodeRequest represents a request to check a code before checkout
+type ValidatePromotionCodeRequest struct {
+	Code string           `json:"code" validate:"required"`
+	Tier SubscriptionTier `json:"tier" validate:"required,oneof=basic pro enterprise"`
+}
+
+// ValidatePromotionCodeResponse represents the result of checking a promotion code.
+// Reason is one of the PromotionCode* constants when the code is not valid.
+type ValidatePromotionCodeResponse struct {
+	Valid         bool           `json:"valid"`
+	Reason        string         `json:"reason,omitempty"`
+	PromotionCode *PromotionCode `json:"promotion_code,omitempty"`
+}
+
+// ExtendTrialRequest represents an admin request to move a subscription's trial end
+type ExtendTrialRequest struct {
+	TrialEndsAt time.Time `json:"trial_ends_at" validate:"required"`
+	Reason      string    `json:"reason,omitempty"`
+}
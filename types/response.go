@@ -0,0 +1,117 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+)
+
+// Response is a typed APIResponse envelope with the same JSON wire format
+type Response[T any] struct {
+	Success bool      `json:"success"`
+	Data    T         `json:"data,omitempty"`
+	Error   *APIError `json:"error,omitempty"`
+	Meta    *Meta     `json:"meta,omitempty"`
+}
+
+// NewResponse wraps data in a successful envelope
+func NewResponse[T any](data T) Response[T] {
+	return Response[T]{Success: true, Data: data}
+}
+
+// NewErrorResponse wraps err in a failed envelope, converting it with ToAPIError
+func NewErrorResponse[T any](err error) Response[T] {
+	return Response[T]{Success: false, Error: ToAPIError(err)}
+}
+
+// NewPageResponse wraps a cursor page in a successful envelope with cursor metadata
+func NewPageResponse[T any](page Page[T]) Response[[]T] {
+	return Response[[]T]{Success: true, Data: page.Items, Meta: page.Meta()}
+}
+
+// NewListResponse wraps one page of an offset listing in a successful envelope with page metadata
+func NewListResponse[T any](items []T, page, limit, total int) Response[[]T] {
+	return Response[[]T]{
+		Success: true,
+		Data:    items,
+		Meta: &Meta{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: TotalPages(total, limit),
+		},
+	}
+}
+
+// Untyped converts the envelope to an APIResponse
+func (r Response[T]) Untyped() APIResponse {
+	resp := APIResponse{Success: r.Success, Error: r.Error, Meta: r.Meta}
+	if !r.omitData() {
+		resp.Data = r.Data
+	}
+	return resp
+}
+
+// Err returns the envelope's error, or nil on success
+func (r Response[T]) Err() error {
+	return ErrorFromResponse(APIResponse{Success: r.Success, Error: r.Error})
+}
+
+// MarshalJSON encodes the envelope exactly as the equivalent APIResponse
+func (r Response[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.Untyped())
+}
+
+// omitData reports whether Data would have been left out of an APIResponse
+func (r Response[T]) omitData() bool {
+	if r.Error != nil {
+		return true
+	}
+	value := reflect.ValueOf(&r.Data).Elem()
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		return value.IsNil()
+	}
+	return false
+}
+
+// DecodeResponse reads an envelope from resp and returns its data and metadata.
+// Error envelopes and error statuses without an envelope are returned as *APIError.
+// The response body is closed.
+func DecodeResponse[T any](resp *http.Response) (T, *Meta, error) {
+	defer resp.Body.Close()
+
+	var envelope Response[T]
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return envelope.Data, nil, fmt.Errorf("read response: %w", err)
+	}
+
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &envelope); err != nil {
+			if resp.StatusCode >= http.StatusBadRequest {
+				return envelope.Data, nil, statusError(resp.StatusCode, string(raw))
+			}
+			return envelope.Data, nil, fmt.Errorf("decode response: %w", err)
+		}
+	}
+
+	if envelope.Error != nil {
+		return envelope.Data, envelope.Meta, envelope.Error
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return envelope.Data, envelope.Meta, statusError(resp.StatusCode, "")
+	}
+	return envelope.Data, envelope.Meta, nil
+}
+
+// statusError builds an APIError for responses that carry no error envelope
+func statusError(status int, details string) *APIError {
+	return &APIError{
+		Code:    CodeForStatus(status),
+		Message: http.StatusText(status),
+		Details: details,
+	}
+}
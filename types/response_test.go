@@ -0,0 +1,125 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestResponseGolden(t *testing.T) {
+	user := &UserResponse{ID: "u1", Email: "ada@example.com", Role: "admin", SubscriptionTier: "free"}
+	page := Page[string]{Items: []string{"u1", "u2"}, Limit: 2, HasMore: true, NextCursor: "bmV4dA", PrevCursor: "cHJldg"}
+
+	// Each typed value must encode to the same JSON as the APIResponse it replaces
+	tests := []struct {
+		name   string
+		typed  interface{}
+		legacy APIResponse
+	}{
+		{"response_struct", NewResponse(user), APIResponse{Success: true, Data: user}},
+		{"response_value", NewResponse(UserExistsResponse{Exists: true}), APIResponse{Success: true, Data: UserExistsResponse{Exists: true}}},
+		{"response_zero_value", NewResponse(0), APIResponse{Success: true, Data: 0}},
+		{"response_nil_pointer", NewResponse[*UserResponse](nil), APIResponse{Success: true}},
+		{"response_nil_slice", NewResponse[[]string](nil), APIResponse{Success: true}},
+		{"response_empty_slice", NewResponse([]string{}), APIResponse{Success: true, Data: []string{}}},
+		{"response_error", NewErrorResponse[*UserResponse](ErrNotFound.WithDetails("user u1")), APIResponse{Error: &APIError{Code: ErrorCodeNotFound, Message: "resource not found", Details: "user u1"}}},
+		{"response_page", NewPageResponse(page), APIResponse{Success: true, Data: page.Items, Meta: &Meta{Limit: 2, NextCursor: "bmV4dA", PrevCursor: "cHJldg"}}},
+		{"response_list", NewListResponse([]string{"u3"}, 2, 2, 3), APIResponse{Success: true, Data: []string{"u3"}, Meta: &Meta{Page: 2, Limit: 2, Total: 3, TotalPages: 2}}},
+		{"page", page, APIResponse{Success: true, Data: map[string]interface{}{
+			"items": []string{"u1", "u2"}, "limit": 2, "has_more": true, "next_cursor": "bmV4dA", "prev_cursor": "cHJldg",
+		}}},
+		{"page_last", Page[string]{Items: []string{}, Limit: 20}, APIResponse{Success: true, Data: map[string]interface{}{
+			"items": []string{}, "limit": 20, "has_more": false,
+		}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.typed)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			golden := filepath.Join("testdata", tt.name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatalf("write golden: %v", err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("read golden: %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("Marshal() =\n%s\nwant\n%s", got, want)
+			}
+
+			legacy, err := json.Marshal(tt.legacy)
+			if err != nil {
+				t.Fatalf("Marshal(legacy) error = %v", err)
+			}
+			if _, isPage := tt.typed.(Page[string]); isPage {
+				// Page is the data of a legacy envelope; compare it inside one
+				legacy = extractData(t, legacy)
+			}
+			if !jsonEqual(t, got, legacy) {
+				t.Errorf("Marshal() = %s, legacy wire shape %s", got, legacy)
+			}
+		})
+	}
+}
+
+func TestResponseDecodesGolden(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "response_page.golden"))
+	if err != nil {
+		t.Fatalf("read golden: %v", err)
+	}
+	var resp Response[[]string]
+	if err := json.Unmarshal(data, &resp); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !resp.Success || !reflect.DeepEqual(resp.Data, []string{"u1", "u2"}) || resp.Meta == nil || resp.Meta.NextCursor != "bmV4dA" || resp.Err() != nil {
+		t.Errorf("Unmarshal() = %+v", resp)
+	}
+
+	data, err = os.ReadFile(filepath.Join("testdata", "response_error.golden"))
+	if err != nil {
+		t.Fatalf("read golden: %v", err)
+	}
+	var failed Response[*UserResponse]
+	if err := json.Unmarshal(data, &failed); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if failed.Success || failed.Data != nil || failed.Error == nil || failed.Error.Code != ErrorCodeNotFound || failed.Error.Details != "user u1" {
+		t.Errorf("Unmarshal() = %+v", failed)
+	}
+}
+
+// extractData returns the raw data field of an encoded envelope
+func extractData(t *testing.T, envelope []byte) []byte {
+	t.Helper()
+	var raw struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(envelope, &raw); err != nil {
+		t.Fatalf("Unmarshal(envelope) error = %v", err)
+	}
+	return raw.Data
+}
+
+// jsonEqual compares two JSON documents ignoring object key order
+func jsonEqual(t *testing.T, a, b []byte) bool {
+	t.Helper()
+	var va, vb interface{}
+	if err := json.Unmarshal(a, &va); err != nil {
+		t.Fatalf("Unmarshal(%s) error = %v", a, err)
+	}
+	if err := json.Unmarshal(b, &vb); err != nil {
+		t.Fatalf("Unmarshal(%s) error = %v", b, err)
+	}
+	return reflect.DeepEqual(va, vb)
+}
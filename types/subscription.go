@@ -2,12 +2,16 @@ package types
 
 import (
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // Subscription represents the core subscription entity
 type Subscription struct {
 	ID                 string     `json:"id"`
 	UserID             string     `json:"user_id"`
+	OrgID              *uuid.UUID `json:"org_id,omitempty"`
+	Seats              int        `json:"seats,omitempty"`
 	StripeCustomerID   string     `json:"stripe_customer_id"`
 	StripeSubscriptionID string   `json:"stripe_subscription_id"`
 	Plan               string     `json:"plan"`
@@ -18,6 +22,8 @@ type Subscription struct {
 	CurrentPeriodEnd   time.Time  `json:"current_period_end"`
 	CanceledAt         *time.Time `json:"canceled_at,omitempty"`
 	TrialEndsAt        *time.Time `json:"trial_ends_at,omitempty"`
+	Discount           *Discount  `json:"discount,omitempty"`
+	BillingCycle       *BillingCycle `json:"billing_cycle,omitempty"`
 	CreatedAt          time.Time  `json:"created_at"`
 	UpdatedAt          time.Time  `json:"updated_at"`
 }
@@ -31,6 +37,27 @@ const (
 	SubscriptionStatusPastDue   SubscriptionStatus = "past_due"
 	SubscriptionStatusTrialing  SubscriptionStatus = "trialing"
 	SubscriptionStatusIncomplete SubscriptionStatus = "incomplete"
+	SubscriptionStatusIncompleteExpired SubscriptionStatus = "incomplete_expired"
+	SubscriptionStatusUnpaid    SubscriptionStatus = "unpaid"
+	SubscriptionStatusPaused    SubscriptionStatus = "paused"
+)
+
+// Valid reports whether the status is one of the known Stripe subscription statuses
+func (s SubscriptionStatus) Valid() bool {
+	switch s {
+	case SubscriptionStatusActive, SubscriptionStatusCanceled, SubscriptionStatusPastDue,
+		SubscriptionStatusTrialing, SubscriptionStatusIncomplete, SubscriptionStatusIncompleteExpired,
+		SubscriptionStatusUnpaid, SubscriptionStatusPaused:
+		return true
+	}
+	return false
+}
+
+// Subscription error codes
+const (
+	// Deprecated: status transitions are not specific to subscriptions. Use ErrorCodeInvalidTransition.
+	SubscriptionErrorInvalidTransition = ErrorCodeInvalidTransition
+	SubscriptionErrorQuotaExceeded     = "quota_exceeded"
 )
 
 // SubscriptionTier represents valid subscription tiers
@@ -42,11 +69,43 @@ const (
 	SubscriptionTierEnterprise SubscriptionTier = "enterprise"
 )
 
+// BillingInterval is the calendar unit a subscription renews on
+type BillingInterval string
+
+const (
+	BillingIntervalDay   BillingInterval = "day"
+	BillingIntervalWeek  BillingInterval = "week"
+	BillingIntervalMonth BillingInterval = "month"
+	BillingIntervalYear  BillingInterval = "year"
+)
+
+// BillingCycle is when a subscription renews: every IntervalCount intervals counted from Anchor.
+// Monthly and yearly periods end on the anchor's day of month, or the last day of shorter months.
+type BillingCycle struct {
+	Interval      BillingInterval `json:"interval"`
+	IntervalCount int             `json:"interval_count"`
+	Anchor        time.Time       `json:"anchor"`
+}
+
+// Valid reports whether the cycle has a known interval, a positive count and an anchor
+func (c BillingCycle) Valid() bool {
+	switch c.Interval {
+	case BillingIntervalDay, BillingIntervalWeek, BillingIntervalMonth, BillingIntervalYear:
+		return c.IntervalCount > 0 && !c.Anchor.IsZero()
+	}
+	return false
+}
+
 // CheckoutSessionRequest represents the request for creating a checkout session
 type CheckoutSessionRequest struct {
 	CustomerID string `json:"customerId" validate:"required"`
 	Email      string `json:"email" validate:"required,email"`
 	Tier       string `json:"tier" validate:"required"`
+	// OrgID buys the subscription for an organization instead of the user
+	OrgID *uuid.UUID `json:"orgId,omitempty"`
+	Seats int        `json:"seats,omitempty" validate:"omitempty,min=1"`
+	// PromotionCode is checked with ValidatePromotionCode before checkout
+	PromotionCode string `json:"promotionCode,omitempty"`
 }
 
 // CheckoutSessionResponse represents the response from creating a checkout session
@@ -57,6 +116,8 @@ type CheckoutSessionResponse struct {
 // SubscriptionCreateRequest represents the request to create a subscription
 type SubscriptionCreateRequest struct {
 	UserID               string                `json:"user_id" validate:"required"`
+	OrgID                *uuid.UUID            `json:"org_id,omitempty"`
+	Seats                int                   `json:"seats,omitempty" validate:"omitempty,min=1"`
 	StripeCustomerID     string                `json:"stripe_customer_id" validate:"required"`
 	StripeSubscriptionID string                `json:"stripe_subscription_id" validate:"required"`
 	Plan                 string                `json:"plan" validate:"required"`
@@ -66,6 +127,7 @@ type SubscriptionCreateRequest struct {
 	CurrentPeriodStart   time.Time             `json:"current_period_start"`
 	CurrentPeriodEnd     time.Time             `json:"current_period_end"`
 	TrialEndsAt          *time.Time            `json:"trial_ends_at,omitempty"`
+	BillingCycle         *BillingCycle         `json:"billing_cycle,omitempty"`
 }
 
 // SubscriptionUpdateRequest represents the request to update a subscription
@@ -74,17 +136,26 @@ type SubscriptionUpdateRequest struct {
 	Tier                 *SubscriptionTier   `json:"tier,omitempty"`
 	Plan                 *string             `json:"plan,omitempty"`
 	StripeCustomerID     *string             `json:"stripe_customer_id,omitempty"`
+	Seats                *int                `json:"seats,omitempty" validate:"omitempty,min=1"`
 	HasActiveSubscription *bool              `json:"has_active_subscription,omitempty"`
 	CurrentPeriodStart   *time.Time          `json:"current_period_start,omitempty"`
 	CurrentPeriodEnd     *time.Time          `json:"current_period_end,omitempty"`
 	CanceledAt           *time.Time          `json:"canceled_at,omitempty"`
 	TrialEndsAt          *time.Time          `json:"trial_ends_at,omitempty"`
+	BillingCycle         *BillingCycle       `json:"billing_cycle,omitempty"`
+}
+
+// SeatUpdateRequest represents the request to change the seat count of an organization subscription
+type SeatUpdateRequest struct {
+	Seats int `json:"seats" validate:"required,min=1"`
 }
 
 // SubscriptionResponse represents the public subscription data
 type SubscriptionResponse struct {
 	ID                 string             `json:"id"`
 	UserID             string             `json:"user_id"`
+	OrgID              *uuid.UUID         `json:"org_id,omitempty"`
+	Seats              int                `json:"seats,omitempty"`
 	Plan               string             `json:"plan"`
 	Tier               SubscriptionTier   `json:"tier"`
 	Status             SubscriptionStatus `json:"status"`
@@ -93,6 +164,8 @@ type SubscriptionResponse struct {
 	CurrentPeriodEnd   time.Time          `json:"current_period_end"`
 	CanceledAt         *time.Time         `json:"canceled_at,omitempty"`
 	TrialEndsAt        *time.Time         `json:"trial_ends_at,omitempty"`
+	Discount           *Discount          `json:"discount,omitempty"`
+	BillingCycle       *BillingCycle      `json:"billing_cycle,omitempty"`
 	CreatedAt          time.Time          `json:"created_at"`
 }
 
@@ -101,6 +174,8 @@ func (s *Subscription) ToResponse() SubscriptionResponse {
 	return SubscriptionResponse{
 		ID:                 s.ID,
 		UserID:             s.UserID,
+		OrgID:              s.OrgID,
+		Seats:              s.Seats,
 		Plan:               s.Plan,
 		Tier:               SubscriptionTier(s.Tier),
 		Status:             SubscriptionStatus(s.Status),
@@ -109,6 +184,8 @@ func (s *Subscription) ToResponse() SubscriptionResponse {
 		CurrentPeriodEnd:   s.CurrentPeriodEnd,
 		CanceledAt:         s.CanceledAt,
 		TrialEndsAt:        s.TrialEndsAt,
+		Discount:           s.Discount,
+		BillingCycle:       s.BillingCycle,
 		CreatedAt:          s.CreatedAt,
 	}
 }
@@ -121,9 +198,48 @@ type UserSubscriptionResponse struct {
 
 // StripeWebhookEvent represents incoming Stripe webhook events
 type StripeWebhookEvent struct {
-	Type   string      `json:"type"`
-	Data   interface{} `json:"data"`
-	Object string      `json:"object"`
+	ID      string      `json:"id,omitempty"`
+	Type    string      `json:"type"`
+	Created int64       `json:"created,omitempty"`
+	Data    interface{} `json:"data"`
+	Object  string      `json:"object"`
+}
+
+// PlanChangeTiming controls when a plan change takes effect
+type PlanChangeTiming string
+
+const (
+	PlanChangeImmediate   PlanChangeTiming = "immediate"
+	PlanChangeAtPeriodEnd PlanChangeTiming = "end_of_period"
+)
+
+// PlanChangeRequest represents a request to preview or apply an upgrade or downgrade
+type PlanChangeRequest struct {
+	Tier   SubscriptionTier `json:"tier" validate:"required,oneof=basic pro enterprise"`
+	Plan   string           `json:"plan,omitempty"`
+	Timing PlanChangeTiming `json:"timing" validate:"required,oneof=immediate end_of_period"`
+}
+
+// PlanChangePreview represents the cost of a plan change.
+// Immediate changes credit the unused part of the current plan and charge the rest of the period on the new one;
+// end-of-period changes are not prorated.
+type PlanChangePreview struct {
+	SubscriptionID   string           `json:"subscription_id"`
+	CurrentTier      SubscriptionTier `json:"current_tier"`
+	NewTier          SubscriptionTier `json:"new_tier"`
+	Timing           PlanChangeTiming `json:"timing"`
+	EffectiveAt      time.Time        `json:"effective_at"`
+	CreditCents      int64            `json:"credit_cents"`
+	ChargeCents      int64            `json:"charge_cents"`
+	AmountDueCents   int64            `json:"amount_due_cents"`
+	Currency         string           `json:"currency,omitempty"`
+	CurrentPeriodEnd time.Time        `json:"current_period_end"`
+}
+
+// PlanChangeResponse represents the result of applying a plan change
+type PlanChangeResponse struct {
+	Subscription SubscriptionResponse `json:"subscription"`
+	Preview      PlanChangePreview    `json:"preview"`
 }
 
 // GetUserSubscriptionRequest represents the request to get a user's subscription
@@ -136,6 +252,57 @@ func (s *SubscriptionResponse) HasActiveSubscription() bool {
 	return s.Status == SubscriptionStatusActive || s.Status == SubscriptionStatusTrialing
 }
 
+// IsOrgOwned reports whether the subscription belongs to an organization rather than a single user
+func (s *SubscriptionResponse) IsOrgOwned() bool {
+	return s.OrgID != nil
+}
+
+// Rank orders tiers from basic to enterprise; unknown tiers rank lowest
+func (t SubscriptionTier) Rank() int {
+	switch t {
+	case SubscriptionTierBasic:
+		return 1
+	case SubscriptionTierPro:
+		return 2
+	case SubscriptionTierEnterprise:
+		return 3
+	}
+	return 0
+}
+
+// SubscriptionSource tells where a user's effective subscription comes from
+type SubscriptionSource string
+
+const (
+	SubscriptionSourceUser         SubscriptionSource = "user"
+	SubscriptionSourceOrganization SubscriptionSource = "organization"
+)
+
+// ResolveSubscription picks a user's effective subscription from their own and their organization's.
+// An active or trialing subscription beats an inactive one; between two active ones the higher tier wins,
+// and the user's own wins a tie. When neither is active the user's own is returned, then the organization's,
+// so the inactive status stays visible. Either argument may be nil.
+func ResolveSubscription(own, org *SubscriptionResponse) (*SubscriptionResponse, SubscriptionSource) {
+	ownActive := own != nil && own.HasActiveSubscription()
+	orgActive := org != nil && org.HasActiveSubscription()
+	switch {
+	case ownActive && orgActive:
+		if org.Tier.Rank() > own.Tier.Rank() {
+			return org, SubscriptionSourceOrganization
+		}
+		return own, SubscriptionSourceUser
+	case ownActive:
+		return own, SubscriptionSourceUser
+	case orgActive:
+		return org, SubscriptionSourceOrganization
+	case own != nil:
+		return own, SubscriptionSourceUser
+	case org != nil:
+		return org, SubscriptionSourceOrganization
+	}
+	return nil, ""
+}
+
 // ErrorResponse represents an error response
 type ErrorResponse struct {
 	Error   string `json:"error"`
@@ -53,6 +53,7 @@ type UserResponse struct {
 	StripeCustomerID      string        `json:"stripe_customer_id,omitempty"`
 	SubscriptionStatus    string        `json:"subscription_status,omitempty"`
 	SubscriptionTier      string        `json:"subscription_tier,omitempty"`
+	SubscriptionSource    SubscriptionSource `json:"subscription_source,omitempty"`
 	CreatedAt             time.Time     `json:"created_at"`
 	Subscription          *Subscription `json:"subscription,omitempty"`
 }
@@ -70,6 +71,21 @@ func (u *User) ToResponse() UserResponse {
 	}
 }
 
+// ApplySubscription fills the subscription fields from the user's effective subscription.
+// The tier is inherited from the organization when ResolveSubscription picks the organization's subscription.
+func (u *UserResponse) ApplySubscription(own, org *SubscriptionResponse) {
+	sub, source := ResolveSubscription(own, org)
+	if sub == nil {
+		u.SubscriptionStatus = ""
+		u.SubscriptionTier = ""
+		u.SubscriptionSource = ""
+		return
+	}
+	u.SubscriptionStatus = string(sub.Status)
+	u.SubscriptionTier = string(sub.Tier)
+	u.SubscriptionSource = source
+}
+
 // EmailVerificationRequest represents email verification update request
 type EmailVerificationRequest struct {
 	EmailVerified     *bool      `json:"email_verified,omitempty"`
@@ -109,10 +125,12 @@ type VerifyEmailResponse struct {
 	User    UserResponse `json:"user"`
 }
 
-// UserListQuery represents query parameters for listing users
+// UserListQuery represents query parameters for listing users.
+// When Cursor is set, Page is ignored.
 type UserListQuery struct {
 	Page          int        `json:"page,omitempty"`
 	Limit         int        `json:"limit,omitempty"`
+	Cursor        string     `json:"cursor,omitempty"`
 	Role          string     `json:"role,omitempty"`
 	OrgID         *uuid.UUID `json:"org_id,omitempty"`
 	EmailVerified *bool      `json:"email_verified,omitempty"`
@@ -125,4 +143,16 @@ type UserListResponse struct {
 	Page       int            `json:"page"`
 	Limit      int            `json:"limit"`
 	TotalPages int            `json:"total_pages"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+	PrevCursor string         `json:"prev_cursor,omitempty"`
+}
+
+// PageLimit returns the requested limit clamped to DefaultLimit/MaxLimit
+func (q UserListQuery) PageLimit() int {
+	return NormalizeLimit(q.Limit)
+}
+
+// UserExistsResponse represents the response for a user existence check
+type UserExistsResponse struct {
+	Exists bool `json:"exists"`
 }
\ No newline at end of file
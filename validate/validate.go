@@ -0,0 +1,298 @@
+package validate
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+// FieldError describes a single failing validation rule
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// Errors lists every field that failed validation
+type Errors []FieldError
+
+// Error implements the error interface
+func (e Errors) Error() string {
+	return "validation failed: " + e.Details()
+}
+
+// Details renders the failures in a form suitable for types.APIError.Details
+func (e Errors) Details() string {
+	parts := make([]string, len(e))
+	for i, fe := range e {
+		parts[i] = fe.Field + ": " + fe.Message
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Is reports whether target matches the validation error code
+func (e Errors) Is(target error) bool {
+	return e.APIError().Is(target)
+}
+
+// APIError converts the failures to a validation APIError
+func (e Errors) APIError() *types.APIError {
+	return &types.APIError{
+		Code:    types.ErrorCodeValidation,
+		Message: "request validation failed",
+		Details: e.Details(),
+	}
+}
+
+// Validate checks v against the `validate:` struct tags on its fields.
+// Supported rules are required, omitempty, email, min, max and oneof.
+// It returns Errors when any rule fails, or a plain error for malformed tags.
+func Validate(v any) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return fmt.Errorf("validate: nil %s", rv.Type())
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("validate: expected struct, got %s", rv.Kind())
+	}
+
+	var errs Errors
+	if err := validateStruct(rv, "", &errs); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateStruct(rv reflect.Value, prefix string, errs *Errors) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		value := rv.Field(i)
+
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			if nested, ok := structValue(value); ok {
+				if err := validateStruct(nested, prefix, errs); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		name := prefix + jsonName(field)
+		if tag := field.Tag.Get("validate"); tag != "" && tag != "-" {
+			if err := validateField(value, name, tag, errs); err != nil {
+				return err
+			}
+		}
+		if err := validateNested(value, name, errs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateNested descends into struct fields and slices of structs
+func validateNested(value reflect.Value, name string, errs *Errors) error {
+	if nested, ok := structValue(value); ok {
+		return validateStruct(nested, name+".", errs)
+	}
+	if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
+		for i := 0; i < value.Len(); i++ {
+			if nested, ok := structValue(value.Index(i)); ok {
+				if err := validateStruct(nested, fmt.Sprintf("%s[%d].", name, i), errs); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// structValue dereferences pointers and reports whether the result is a validatable struct
+func structValue(value reflect.Value) (reflect.Value, bool) {
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return value, false
+		}
+		value = value.Elem()
+	}
+	// Opaque value types such as time.Time carry no tags worth walking
+	if value.Kind() != reflect.Struct || value.Type().PkgPath() != "" && !isLocalType(value.Type()) {
+		return value, false
+	}
+	return value, true
+}
+
+// isLocalType reports whether t belongs to this module, so third-party structs are not walked
+func isLocalType(t reflect.Type) bool {
+	return strings.HasPrefix(t.PkgPath(), "github.com/jonnyt98/atlas-shared/")
+}
+
+func validateField(value reflect.Value, name, tag string, errs *Errors) error {
+	for _, rule := range strings.Split(tag, ",") {
+		rule = strings.TrimSpace(rule)
+		key, param, _ := strings.Cut(rule, "=")
+
+		switch key {
+		case "omitempty":
+			if isEmpty(value) {
+				return nil
+			}
+			continue
+		case "required":
+			if isEmpty(value) {
+				errs.add(name, key, "", "is required")
+				return nil
+			}
+			continue
+		}
+
+		// Remaining rules apply to the underlying value and skip nil pointers
+		target := value
+		for target.Kind() == reflect.Pointer {
+			if target.IsNil() {
+				return nil
+			}
+			target = target.Elem()
+		}
+
+		var (
+			ok      bool
+			message string
+			err     error
+		)
+		switch key {
+		case "email":
+			ok, message = checkEmail(target)
+		case "min":
+			ok, message, err = checkBound(target, param, true)
+		case "max":
+			ok, message, err = checkBound(target, param, false)
+		case "oneof":
+			ok, message = checkOneOf(target, param)
+		default:
+			return fmt.Errorf("validate: unknown rule %q on field %s", key, name)
+		}
+		if err != nil {
+			return fmt.Errorf("validate: rule %q on field %s: %w", rule, name, err)
+		}
+		if !ok {
+			errs.add(name, key, param, message)
+		}
+	}
+	return nil
+}
+
+func (e *Errors) add(field, rule, param, message string) {
+	*e = append(*e, FieldError{Field: field, Rule: rule, Param: param, Message: message})
+}
+
+func checkEmail(value reflect.Value) (bool, string) {
+	const message = "must be a valid email address"
+	if value.Kind() != reflect.String {
+		return false, message
+	}
+	s := value.String()
+	if s == "" {
+		return true, ""
+	}
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s {
+		return false, message
+	}
+	return true, ""
+}
+
+// checkBound enforces min/max on string length, collection length or numeric value
+func checkBound(value reflect.Value, param string, isMin bool) (bool, string, error) {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return false, "", err
+	}
+
+	var (
+		actual float64
+		unit   string
+	)
+	switch value.Kind() {
+	case reflect.String:
+		actual = float64(utf8.RuneCountInString(value.String()))
+		unit = " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		actual = float64(value.Len())
+		unit = " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		actual = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		actual = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		actual = value.Float()
+	default:
+		return false, "", fmt.Errorf("unsupported kind %s", value.Kind())
+	}
+
+	if isMin && actual < limit {
+		return false, "must be at least " + param + unit, nil
+	}
+	if !isMin && actual > limit {
+		return false, "must be at most " + param + unit, nil
+	}
+	return true, "", nil
+}
+
+func checkOneOf(value reflect.Value, param string) (bool, string) {
+	options := strings.Fields(param)
+	var actual string
+	switch value.Kind() {
+	case reflect.String:
+		actual = value.String()
+		if actual == "" {
+			return true, ""
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		actual = strconv.FormatInt(value.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		actual = strconv.FormatUint(value.Uint(), 10)
+	}
+	for _, option := range options {
+		if actual == option {
+			return true, ""
+		}
+	}
+	return false, "must be one of " + strings.Join(options, ", ")
+}
+
+func isEmpty(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Slice, reflect.Map:
+		return value.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return value.IsNil()
+	default:
+		return value.IsZero()
+	}
+}
+
+// jsonName returns the JSON field name used in error reports
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
@@ -0,0 +1,167 @@
+package validate
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jonnyt98/atlas-shared/types"
+)
+
+type address struct {
+	City string `json:"city" validate:"required"`
+}
+
+type profile struct {
+	Email     string    `json:"email" validate:"required,email"`
+	Name      string    `json:"name,omitempty" validate:"omitempty,min=2,max=5"`
+	Age       int       `json:"age" validate:"min=18,max=130"`
+	Role      string    `json:"role" validate:"oneof=admin user"`
+	Tags      []string  `json:"tags" validate:"max=2"`
+	Nickname  *string   `json:"nickname" validate:"min=3"`
+	Home      address   `json:"home"`
+	Previous  []address `json:"previous"`
+	Work      *address  `json:"work,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	internal  string    `validate:"required"`
+}
+
+func validProfile() profile {
+	return profile{Email: "a@example.com", Age: 30, Role: "user", Home: address{City: "Oslo"}}
+}
+
+func TestValidate(t *testing.T) {
+	short := "ab"
+	tests := []struct {
+		name   string
+		modify func(p *profile)
+		want   []FieldError
+	}{
+		{"valid", func(p *profile) {}, nil},
+		{"required", func(p *profile) { p.Email = "" }, []FieldError{
+			{Field: "email", Rule: "required", Message: "is required"},
+		}},
+		{"email", func(p *profile) { p.Email = "Alice <a@example.com>" }, []FieldError{
+			{Field: "email", Rule: "email", Message: "must be a valid email address"},
+		}},
+		{"omitempty skips empty", func(p *profile) { p.Name = "" }, nil},
+		{"min string", func(p *profile) { p.Name = "é" }, []FieldError{
+			{Field: "name", Rule: "min", Param: "2", Message: "must be at least 2 characters"},
+		}},
+		{"max string counts runes", func(p *profile) { p.Name = "ééééé" }, nil},
+		{"max string", func(p *profile) { p.Name = "abcdef" }, []FieldError{
+			{Field: "name", Rule: "max", Param: "5", Message: "must be at most 5 characters"},
+		}},
+		{"min int", func(p *profile) { p.Age = 17 }, []FieldError{
+			{Field: "age", Rule: "min", Param: "18", Message: "must be at least 18"},
+		}},
+		{"max slice", func(p *profile) { p.Tags = []string{"a", "b", "c"} }, []FieldError{
+			{Field: "tags", Rule: "max", Param: "2", Message: "must be at most 2 items"},
+		}},
+		{"oneof", func(p *profile) { p.Role = "owner" }, []FieldError{
+			{Field: "role", Rule: "oneof", Param: "admin user", Message: "must be one of admin, user"},
+		}},
+		{"oneof allows empty string", func(p *profile) { p.Role = "" }, nil},
+		{"nil pointer skips rules", func(p *profile) { p.Nickname = nil }, nil},
+		{"pointer target", func(p *profile) { p.Nickname = &short }, []FieldError{
+			{Field: "nickname", Rule: "min", Param: "3", Message: "must be at least 3 characters"},
+		}},
+		{"nested struct", func(p *profile) { p.Home.City = "" }, []FieldError{
+			{Field: "home.city", Rule: "required", Message: "is required"},
+		}},
+		{"slice of structs", func(p *profile) { p.Previous = []address{{City: "Rome"}, {}} }, []FieldError{
+			{Field: "previous[1].city", Rule: "required", Message: "is required"},
+		}},
+		{"nested pointer", func(p *profile) { p.Work = &address{} }, []FieldError{
+			{Field: "work.city", Rule: "required", Message: "is required"},
+		}},
+		{"every failure is reported", func(p *profile) { p.Email = ""; p.Age = 200 }, []FieldError{
+			{Field: "email", Rule: "required", Message: "is required"},
+			{Field: "age", Rule: "max", Param: "130", Message: "must be at most 130"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := validProfile()
+			tt.modify(&p)
+			err := Validate(&p)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			var errs Errors
+			if !errors.As(err, &errs) {
+				t.Fatalf("Validate() error = %v, want Errors", err)
+			}
+			if !reflect.DeepEqual([]FieldError(errs), tt.want) {
+				t.Errorf("Validate() = %+v, want %+v", errs, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorsDetails(t *testing.T) {
+	errs := Errors{
+		{Field: "email", Rule: "required", Message: "is required"},
+		{Field: "age", Rule: "min", Param: "18", Message: "must be at least 18"},
+	}
+	want := "email: is required; age: must be at least 18"
+	if got := errs.Details(); got != want {
+		t.Errorf("Details() = %q, want %q", got, want)
+	}
+	if got := errs.Error(); got != "validation failed: "+want {
+		t.Errorf("Error() = %q", got)
+	}
+
+	apiErr := errs.APIError()
+	if apiErr.Code != types.ErrorCodeValidation || apiErr.Details != want {
+		t.Errorf("APIError() = %+v", apiErr)
+	}
+	if !errors.Is(errs, types.ErrValidation) {
+		t.Error("errors.Is(errs, types.ErrValidation) = false")
+	}
+}
+
+func TestValidateMalformedInput(t *testing.T) {
+	type badRule struct {
+		Name string `validate:"uppercase"`
+	}
+	type badParam struct {
+		Name string `validate:"min=two"`
+	}
+	var nilProfile *profile
+
+	for name, v := range map[string]any{
+		"unknown rule": badRule{Name: "x"},
+		"bad param":    badParam{Name: "x"},
+		"nil pointer":  nilProfile,
+		"not a struct": 42,
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := Validate(v)
+			if err == nil {
+				t.Fatal("Validate() error = nil")
+			}
+			var errs Errors
+			if errors.As(err, &errs) {
+				t.Errorf("Validate() = %v, want a plain error", err)
+			}
+		})
+	}
+}
+
+func TestPhoneProvisionRequestKeepsBindingTags(t *testing.T) {
+	rt := reflect.TypeOf(types.PhoneProvisionRequest{})
+	for _, name := range []string{"UserID", "Capabilities"} {
+		field, _ := rt.FieldByName(name)
+		if field.Tag.Get("binding") != "required" || field.Tag.Get("validate") != "required" {
+			t.Errorf("%s tags = %q, want binding and validate required", name, field.Tag)
+		}
+	}
+	if err := Validate(types.PhoneProvisionRequest{}); err == nil {
+		t.Error("Validate(empty PhoneProvisionRequest) error = nil")
+	}
+}